@@ -0,0 +1,448 @@
+// Package apidoc parses the API description Graylog serves for its API
+// browser: OpenAPI 3, Swagger 2, or the per-resource Swagger 1.2 declarations
+// of older releases.
+package apidoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Parameter struct {
+	Name        string `json:"name"`
+	In          string `json:"in"`
+	Type        string `json:"type,omitempty"`
+	Required    bool   `json:"required"`
+	Description string `json:"description,omitempty"`
+}
+
+// Field is one property of a request body schema; nested properties use
+// dotted names and "[]" for array items.
+type Field struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+}
+
+type Endpoint struct {
+	Method      string      `json:"method"`
+	Path        string      `json:"path"`
+	Summary     string      `json:"summary,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Tags        []string    `json:"tags,omitempty"`
+	Permissions []string    `json:"permissions,omitempty"`
+	Parameters  []Parameter `json:"parameters,omitempty"`
+	BodyType    string      `json:"body_type,omitempty"`
+	Body        []Field     `json:"body,omitempty"`
+}
+
+type Document struct {
+	Format    string     `json:"format"`
+	Endpoints []Endpoint `json:"endpoints"`
+}
+
+// maxSchemaDepth bounds how far nested body schemas are expanded.
+const maxSchemaDepth = 4
+
+var methodOrder = map[string]int{"GET": 0, "HEAD": 1, "POST": 2, "PUT": 3, "PATCH": 4, "DELETE": 5, "OPTIONS": 6}
+
+// Decode reads a JSON or YAML document into a generic map.
+func Decode(data []byte) (map[string]any, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err == nil {
+		return doc, nil
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("not a JSON or YAML document: %w", err)
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("empty API document")
+	}
+	return doc, nil
+}
+
+// ResourcePaths returns the declaration paths listed by a Swagger 1.2
+// resource listing. ok is false when doc is not a listing.
+func ResourcePaths(doc map[string]any) (paths []string, ok bool) {
+	if _, isDecl := doc["resourcePath"]; isDecl {
+		return nil, false
+	}
+	apis, ok := doc["apis"].([]any)
+	if !ok || doc["swaggerVersion"] == nil {
+		return nil, false
+	}
+	for _, api := range apis {
+		if p := str(asMap(api)["path"]); p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths, true
+}
+
+// Parse reads an OpenAPI 3 or Swagger 2 document, or one Swagger 1.2 API
+// declaration.
+func Parse(doc map[string]any) (Document, error) {
+	switch {
+	case doc["openapi"] != nil:
+		defs := asMap(asMap(doc["components"])["schemas"])
+		return Document{Format: "openapi " + str(doc["openapi"]), Endpoints: parsePaths(doc, defs)}, nil
+	case doc["swagger"] != nil:
+		return Document{Format: "swagger " + str(doc["swagger"]), Endpoints: parsePaths(doc, asMap(doc["definitions"]))}, nil
+	case doc["swaggerVersion"] != nil && doc["resourcePath"] != nil:
+		return Document{Format: "swagger " + str(doc["swaggerVersion"]), Endpoints: parseDeclaration(doc)}, nil
+	}
+	return Document{}, fmt.Errorf("unrecognized API document (expected OpenAPI or Swagger)")
+}
+
+// Merge combines documents, e.g. the declarations of a Swagger 1.2 listing.
+func Merge(docs ...Document) Document {
+	var out Document
+	for _, d := range docs {
+		if out.Format == "" {
+			out.Format = d.Format
+		}
+		out.Endpoints = append(out.Endpoints, d.Endpoints...)
+	}
+	sortEndpoints(out.Endpoints)
+	return out
+}
+
+// Filter returns endpoints whose method, path, summary or tags contain text,
+// case-insensitively.
+func (d Document) Filter(text string) []Endpoint {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" {
+		return d.Endpoints
+	}
+	var out []Endpoint
+	for _, e := range d.Endpoints {
+		hay := strings.ToLower(strings.Join(append([]string{e.Method, e.Path, e.Summary}, e.Tags...), " "))
+		if strings.Contains(hay, text) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Match returns the endpoints for a path. Concrete paths match templates, so
+// /streams/abc finds /streams/{streamId}; exact template matches win.
+func (d Document) Match(p string) []Endpoint {
+	p = "/" + strings.Trim(p, "/")
+	var exact, templated []Endpoint
+	for _, e := range d.Endpoints {
+		switch {
+		case "/"+strings.Trim(e.Path, "/") == p:
+			exact = append(exact, e)
+		case matchTemplate(e.Path, p):
+			templated = append(templated, e)
+		}
+	}
+	if len(exact) > 0 {
+		return exact
+	}
+	return templated
+}
+
+func matchTemplate(template, p string) bool {
+	ts := strings.Split(strings.Trim(template, "/"), "/")
+	ps := strings.Split(strings.Trim(p, "/"), "/")
+	if len(ts) != len(ps) {
+		return false
+	}
+	for i := range ts {
+		isParam := strings.HasPrefix(ts[i], "{") && strings.HasSuffix(ts[i], "}")
+		if !isParam && ts[i] != ps[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func parsePaths(doc, defs map[string]any) []Endpoint {
+	var out []Endpoint
+	for p, item := range asMap(doc["paths"]) {
+		pathItem := asMap(item)
+		shared := asSlice(pathItem["parameters"])
+		for method, op := range pathItem {
+			m := strings.ToUpper(method)
+			if _, ok := methodOrder[m]; !ok {
+				continue
+			}
+			operation := asMap(op)
+			e := Endpoint{
+				Method:      m,
+				Path:        p,
+				Summary:     str(operation["summary"]),
+				Description: str(operation["description"]),
+				Tags:        strs(operation["tags"]),
+				Permissions: permissions(operation),
+			}
+			for _, raw := range append(append([]any{}, shared...), asSlice(operation["parameters"])...) {
+				param := asMap(resolve(raw, defs, doc))
+				if str(param["in"]) == "body" {
+					e.BodyType, e.Body = bodySchema(param["schema"], defs)
+					continue
+				}
+				e.Parameters = append(e.Parameters, Parameter{
+					Name:        str(param["name"]),
+					In:          str(param["in"]),
+					Type:        typeName(firstNonNil(param["schema"], param), defs),
+					Required:    param["required"] == true,
+					Description: str(param["description"]),
+				})
+			}
+			if rb := asMap(resolve(operation["requestBody"], defs, doc)); rb != nil {
+				e.BodyType, e.Body = bodySchema(contentSchema(asMap(rb["content"])), defs)
+			}
+			out = append(out, e)
+		}
+	}
+	sortEndpoints(out)
+	return out
+}
+
+func parseDeclaration(doc map[string]any) []Endpoint {
+	models := asMap(doc["models"])
+	var out []Endpoint
+	for _, api := range asSlice(doc["apis"]) {
+		a := asMap(api)
+		for _, op := range asSlice(a["operations"]) {
+			operation := asMap(op)
+			e := Endpoint{
+				Method:      strings.ToUpper(str(operation["method"])),
+				Path:        str(a["path"]),
+				Summary:     str(operation["summary"]),
+				Description: str(operation["notes"]),
+				Permissions: permissions(operation),
+			}
+			if rp := strings.Trim(str(doc["resourcePath"]), "/"); rp != "" {
+				e.Tags = []string{rp}
+			}
+			for _, raw := range asSlice(operation["parameters"]) {
+				param := asMap(raw)
+				if str(param["paramType"]) == "body" {
+					e.BodyType, e.Body = bodySchema(param, models)
+					continue
+				}
+				e.Parameters = append(e.Parameters, Parameter{
+					Name:        str(param["name"]),
+					In:          str(param["paramType"]),
+					Type:        typeName(param, models),
+					Required:    param["required"] == true,
+					Description: str(param["description"]),
+				})
+			}
+			out = append(out, e)
+		}
+	}
+	sortEndpoints(out)
+	return out
+}
+
+// permissions collects vendor extensions naming required permissions and
+// OAuth-style security scopes. Documents without either yield none.
+func permissions(operation map[string]any) []string {
+	var out []string
+	for k, v := range operation {
+		if strings.HasPrefix(k, "x-") && strings.Contains(strings.ToLower(k), "permission") {
+			if s := str(v); s != "" {
+				out = append(out, s)
+			}
+			out = append(out, strs(v)...)
+		}
+	}
+	for _, req := range asSlice(operation["security"]) {
+		for _, scopes := range asMap(req) {
+			out = append(out, strs(scopes)...)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func contentSchema(content map[string]any) any {
+	if s, ok := asMap(content["application/json"])["schema"]; ok {
+		return s
+	}
+	for _, mt := range sortedKeys(content) {
+		if s, ok := asMap(content[mt])["schema"]; ok {
+			return s
+		}
+	}
+	return nil
+}
+
+// bodySchema names a body schema and flattens its properties.
+func bodySchema(schema any, defs map[string]any) (string, []Field) {
+	if schema == nil {
+		return "", nil
+	}
+	var fields []Field
+	flatten(schema, defs, "", map[string]bool{}, 0, &fields)
+	return typeName(schema, defs), fields
+}
+
+func flatten(schema any, defs map[string]any, prefix string, seen map[string]bool, depth int, out *[]Field) {
+	name := refName(schema, defs)
+	if name != "" {
+		if seen[name] {
+			return
+		}
+		seen = copySet(seen)
+		seen[name] = true
+	}
+	s := asMap(resolveSchema(schema, defs))
+	if depth >= maxSchemaDepth || s == nil {
+		return
+	}
+	if items, ok := s["items"]; ok {
+		flatten(items, defs, prefix+"[]", seen, depth+1, out)
+		return
+	}
+	required := map[string]bool{}
+	for _, r := range strs(s["required"]) {
+		required[r] = true
+	}
+	props := asMap(s["properties"])
+	for _, k := range sortedKeys(props) {
+		prop := props[k]
+		field := k
+		if prefix != "" {
+			field = prefix + "." + k
+		}
+		*out = append(*out, Field{Name: field, Type: typeName(prop, defs), Required: required[k] || asMap(prop)["required"] == true})
+		flatten(prop, defs, field, seen, depth+1, out)
+	}
+}
+
+// typeName renders a schema type, e.g. "string", "array<Stream>" or "Stream".
+func typeName(schema any, defs map[string]any) string {
+	if name := refName(schema, defs); name != "" {
+		return name
+	}
+	s := asMap(schema)
+	t := str(s["type"])
+	switch {
+	case t == "array":
+		return "array<" + typeName(s["items"], defs) + ">"
+	case t == "object" && s["additionalProperties"] != nil && s["properties"] == nil:
+		if inner, ok := s["additionalProperties"].(map[string]any); ok {
+			return "map<" + typeName(inner, defs) + ">"
+		}
+		return "map"
+	case t != "" && str(s["format"]) != "":
+		return t + "(" + str(s["format"]) + ")"
+	case t == "" && len(asSlice(s["enum"])) > 0:
+		return "enum"
+	}
+	return t
+}
+
+// refName returns the referenced definition of a schema: "$ref" in all
+// formats, or a model name given as "type" in Swagger 1.2.
+func refName(schema any, defs map[string]any) string {
+	s := asMap(schema)
+	if ref := str(s["$ref"]); ref != "" {
+		return ref[strings.LastIndex(ref, "/")+1:]
+	}
+	if t := str(s["type"]); t != "" {
+		if _, ok := defs[t]; ok {
+			return t
+		}
+	}
+	return ""
+}
+
+func resolveSchema(schema any, defs map[string]any) any {
+	if name := refName(schema, defs); name != "" {
+		return defs[name]
+	}
+	return schema
+}
+
+// resolve follows a local "$ref" such as "#/components/parameters/x".
+func resolve(v any, defs, doc map[string]any) any {
+	ref := str(asMap(v)["$ref"])
+	if !strings.HasPrefix(ref, "#/") {
+		return v
+	}
+	var cur any = doc
+	for _, part := range strings.Split(ref[2:], "/") {
+		cur = asMap(cur)[part]
+	}
+	if cur == nil {
+		return v
+	}
+	return cur
+}
+
+func sortEndpoints(list []Endpoint) {
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Path != list[j].Path {
+			return list[i].Path < list[j].Path
+		}
+		return methodOrder[list[i].Method] < methodOrder[list[j].Method]
+	})
+}
+
+func asMap(v any) map[string]any {
+	m, _ := v.(map[string]any)
+	return m
+}
+
+func asSlice(v any) []any {
+	s, _ := v.([]any)
+	return s
+}
+
+func str(v any) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case nil:
+		return ""
+	case map[string]any, []any:
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+func strs(v any) []string {
+	var out []string
+	for _, item := range asSlice(v) {
+		if s := str(item); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func firstNonNil(values ...any) any {
+	for _, v := range values {
+		if asMap(v) != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func copySet(s map[string]bool) map[string]bool {
+	out := make(map[string]bool, len(s)+1)
+	for k := range s {
+		out[k] = true
+	}
+	return out
+}
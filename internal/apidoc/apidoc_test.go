@@ -0,0 +1,150 @@
+package apidoc
+
+import (
+	"reflect"
+	"testing"
+)
+
+const openAPIDoc = `
+openapi: 3.0.1
+paths:
+  /streams/{streamId}:
+    parameters:
+      - name: streamId
+        in: path
+        required: true
+        schema: {type: string}
+    get:
+      summary: Get a single stream
+      tags: [Streams]
+      x-required-permissions: ["streams:read"]
+    put:
+      summary: Update a stream
+      tags: [Streams]
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/UpdateStreamRequest"}
+  /streams:
+    get:
+      summary: Get a list of all streams
+      parameters:
+        - {name: page, in: query, schema: {type: integer, format: int32}}
+components:
+  schemas:
+    UpdateStreamRequest:
+      type: object
+      required: [title]
+      properties:
+        title: {type: string}
+        rules:
+          type: array
+          items: {$ref: "#/components/schemas/Rule"}
+    Rule:
+      type: object
+      properties:
+        field: {type: string}
+        parent: {$ref: "#/components/schemas/UpdateStreamRequest"}
+`
+
+func TestParseOpenAPI(t *testing.T) {
+	t.Parallel()
+
+	raw, err := Decode([]byte(openAPIDoc))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	doc, err := Parse(raw)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if doc.Format != "openapi 3.0.1" || len(doc.Endpoints) != 3 {
+		t.Fatalf("unexpected document: %+v", doc)
+	}
+	if e := doc.Endpoints[0]; e.Path != "/streams" || e.Parameters[0].Type != "integer(int32)" {
+		t.Fatalf("unexpected first endpoint: %+v", e)
+	}
+
+	matches := doc.Match("/streams/abc")
+	if len(matches) != 2 || matches[0].Method != "GET" || matches[1].Method != "PUT" {
+		t.Fatalf("unexpected matches: %+v", matches)
+	}
+	get, put := matches[0], matches[1]
+	if !reflect.DeepEqual(get.Permissions, []string{"streams:read"}) {
+		t.Fatalf("unexpected permissions: %v", get.Permissions)
+	}
+	if len(put.Parameters) != 1 || !put.Parameters[0].Required || put.Parameters[0].In != "path" {
+		t.Fatalf("shared path parameter not applied: %+v", put.Parameters)
+	}
+	wantBody := []Field{
+		{Name: "rules", Type: "array<Rule>"},
+		{Name: "rules[].field", Type: "string"},
+		{Name: "rules[].parent", Type: "UpdateStreamRequest"},
+		{Name: "title", Type: "string", Required: true},
+	}
+	if put.BodyType != "UpdateStreamRequest" || !reflect.DeepEqual(put.Body, wantBody) {
+		t.Fatalf("unexpected body %s: %+v", put.BodyType, put.Body)
+	}
+
+	if got := doc.Filter("list of all"); len(got) != 1 || got[0].Path != "/streams" {
+		t.Fatalf("unexpected filter result: %+v", got)
+	}
+	if got := doc.Match("/streams"); len(got) != 1 {
+		t.Fatalf("expected exact match only, got %+v", got)
+	}
+}
+
+func TestParseSwagger2BodyParameter(t *testing.T) {
+	t.Parallel()
+
+	raw, err := Decode([]byte(`{"swagger":"2.0","paths":{"/system/inputs":{"post":{"parameters":[{"name":"JSON body","in":"body","required":true,"schema":{"$ref":"#/definitions/InputCreateRequest"}}]}}},"definitions":{"InputCreateRequest":{"type":"object","properties":{"global":{"type":"boolean"}}}}}`))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	doc, err := Parse(raw)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	e := doc.Endpoints[0]
+	if e.BodyType != "InputCreateRequest" || len(e.Body) != 1 || e.Body[0].Name != "global" || len(e.Parameters) != 0 {
+		t.Fatalf("unexpected endpoint: %+v", e)
+	}
+}
+
+func TestParseSwagger12(t *testing.T) {
+	t.Parallel()
+
+	listing, _ := Decode([]byte(`{"swaggerVersion":"1.2","apis":[{"path":"/system","description":"System"},{"path":"/streams"}]}`))
+	paths, ok := ResourcePaths(listing)
+	if !ok || !reflect.DeepEqual(paths, []string{"/system", "/streams"}) {
+		t.Fatalf("unexpected listing paths: %v %v", paths, ok)
+	}
+
+	decl, _ := Decode([]byte(`{"swaggerVersion":"1.2","resourcePath":"/streams","apis":[{"path":"/streams/{streamId}/pause","operations":[{"method":"POST","summary":"Pause a stream","parameters":[{"name":"streamId","paramType":"path","type":"String","required":true}]}]},{"path":"/streams","operations":[{"method":"POST","summary":"Create a stream","parameters":[{"name":"JSON body","paramType":"body","type":"CreateStreamRequest","required":true}]}]}],"models":{"CreateStreamRequest":{"id":"CreateStreamRequest","properties":{"title":{"type":"string"}},"required":["title"]}}}`))
+	if _, ok := ResourcePaths(decl); ok {
+		t.Fatalf("declaration must not be treated as a listing")
+	}
+	doc, err := Parse(decl)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	merged := Merge(doc)
+	if merged.Format != "swagger 1.2" || len(merged.Endpoints) != 2 {
+		t.Fatalf("unexpected document: %+v", merged)
+	}
+	create := merged.Endpoints[0]
+	if create.Path != "/streams" || create.BodyType != "CreateStreamRequest" || !create.Body[0].Required || create.Tags[0] != "streams" {
+		t.Fatalf("unexpected create endpoint: %+v", create)
+	}
+	if got := merged.Match("/streams/1/pause"); len(got) != 1 || got[0].Parameters[0].In != "path" {
+		t.Fatalf("unexpected match: %+v", got)
+	}
+}
+
+func TestParseRejectsUnknownDocument(t *testing.T) {
+	t.Parallel()
+
+	if _, err := Parse(map[string]any{"hello": "world"}); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,234 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Resource struct {
+	Name  string
+	Paths []string
+	TTL   time.Duration
+}
+
+// Resources lists the API paths whose GET responses may be cached, with their
+// default TTL. Only exact paths match so live data under the same prefix (for
+// example index set stats) is never served stale.
+var Resources = []Resource{
+	{Name: "streams", Paths: []string{"/streams"}, TTL: 10 * time.Minute},
+	{Name: "inputs", Paths: []string{"/system/inputs"}, TTL: 10 * time.Minute},
+	{Name: "nodes", Paths: []string{"/cluster/nodes"}, TTL: 5 * time.Minute},
+	{Name: "fields", Paths: []string{"/views/fields", "/system/fields"}, TTL: 5 * time.Minute},
+	{Name: "index_sets", Paths: []string{"/system/indices/index_sets"}, TTL: time.Hour},
+}
+
+type Entry struct {
+	Resource  string          `json:"resource"`
+	URL       string          `json:"url"`
+	FetchedAt time.Time       `json:"fetched_at"`
+	Body      json.RawMessage `json:"body"`
+}
+
+type Status struct {
+	Resource string
+	URL      string
+	Age      time.Duration
+	TTL      time.Duration
+	Size     int
+	Fresh    bool
+}
+
+type Store struct {
+	dir     string
+	baseURL string
+	ttls    map[string]time.Duration
+	now     func() time.Time
+}
+
+func RootDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve home dir: %w", err)
+	}
+	return filepath.Join(home, ".cache", "graylogctl"), nil
+}
+
+func ProfileDir(profile string) (string, error) {
+	root, err := RootDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, profile), nil
+}
+
+// New returns a store in dir for responses from baseURL. ttls overrides the
+// default TTL per resource name; a zero TTL disables caching for it.
+func New(dir, baseURL string, ttls map[string]time.Duration) (*Store, error) {
+	merged := make(map[string]time.Duration, len(Resources))
+	for _, r := range Resources {
+		merged[r.Name] = r.TTL
+	}
+	for name, ttl := range ttls {
+		if _, ok := merged[name]; !ok {
+			return nil, fmt.Errorf("unknown cache resource %q (use %s)", name, strings.Join(ResourceNames(), ", "))
+		}
+		if ttl < 0 {
+			return nil, fmt.Errorf("cache ttl for %s must be >= 0", name)
+		}
+		merged[name] = ttl
+	}
+	return &Store{dir: dir, baseURL: strings.TrimRight(baseURL, "/"), ttls: merged, now: time.Now}, nil
+}
+
+func ResourceNames() []string {
+	names := make([]string, 0, len(Resources))
+	for _, r := range Resources {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
+func resourceFor(apiPath string) string {
+	apiPath = "/" + strings.Trim(strings.SplitN(apiPath, "?", 2)[0], "/")
+	for _, r := range Resources {
+		for _, p := range r.Paths {
+			if p == apiPath {
+				return r.Name
+			}
+		}
+	}
+	return ""
+}
+
+// Get returns the cached body for apiPath if it is cacheable and younger than
+// its TTL.
+func (s *Store) Get(apiPath string) ([]byte, bool) {
+	name := resourceFor(apiPath)
+	if name == "" || s.ttls[name] <= 0 {
+		return nil, false
+	}
+	b, err := os.ReadFile(s.file(name, apiPath))
+	if err != nil {
+		return nil, false
+	}
+	var e Entry
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, false
+	}
+	if s.now().Sub(e.FetchedAt) >= s.ttls[name] {
+		return nil, false
+	}
+	return e.Body, true
+}
+
+// Put stores body for apiPath. Failures are ignored; the cache is only an
+// optimisation.
+func (s *Store) Put(apiPath string, body []byte) {
+	name := resourceFor(apiPath)
+	if name == "" || s.ttls[name] <= 0 || !json.Valid(body) {
+		return
+	}
+	b, err := json.Marshal(Entry{Resource: name, URL: s.baseURL + apiPath, FetchedAt: s.now().UTC(), Body: body})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return
+	}
+	path := s.file(name, apiPath)
+	tmp, err := os.CreateTemp(s.dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		return
+	}
+	_ = os.Rename(tmp.Name(), path)
+}
+
+func (s *Store) Status() ([]Status, error) {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("list cache %s: %w", s.dir, err)
+	}
+	var out []Status
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("read cache entry %s: %w", f, err)
+		}
+		var e Entry
+		if err := json.Unmarshal(b, &e); err != nil {
+			continue
+		}
+		age := s.now().Sub(e.FetchedAt)
+		ttl := s.ttls[e.Resource]
+		out = append(out, Status{
+			Resource: e.Resource,
+			URL:      e.URL,
+			Age:      age,
+			TTL:      ttl,
+			Size:     len(e.Body),
+			Fresh:    age < ttl,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Resource != out[j].Resource {
+			return out[i].Resource < out[j].Resource
+		}
+		return out[i].URL < out[j].URL
+	})
+	return out, nil
+}
+
+// Clear removes cached entries for the given resources, or all entries when
+// none are named. It returns the number of files removed.
+func (s *Store) Clear(resources ...string) (int, error) {
+	for _, r := range resources {
+		if _, ok := s.ttls[r]; !ok {
+			return 0, fmt.Errorf("unknown cache resource %q (use %s)", r, strings.Join(ResourceNames(), ", "))
+		}
+	}
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return 0, fmt.Errorf("list cache %s: %w", s.dir, err)
+	}
+	removed := 0
+	for _, f := range files {
+		if len(resources) > 0 && !matchesResource(filepath.Base(f), resources) {
+			continue
+		}
+		if err := os.Remove(f); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return removed, fmt.Errorf("remove cache entry %s: %w", f, err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+func matchesResource(file string, resources []string) bool {
+	for _, r := range resources {
+		if strings.HasPrefix(file, r+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) file(resource, apiPath string) string {
+	sum := sha256.Sum256([]byte(s.baseURL + apiPath))
+	return filepath.Join(s.dir, resource+"-"+hex.EncodeToString(sum[:8])+".json")
+}
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetPutHonoursTTL(t *testing.T) {
+	t.Parallel()
+
+	s, err := New(t.TempDir(), "https://graylog.example.com/api", map[string]time.Duration{"streams": time.Minute})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	now := time.Date(2026, 2, 18, 10, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	if _, ok := s.Get("/streams"); ok {
+		t.Fatalf("expected empty cache")
+	}
+	s.Put("/streams", []byte(`{"streams":[]}`))
+	s.Put("/system/indices/index_sets/stats", []byte(`{"indices":1}`))
+
+	if b, ok := s.Get("/streams"); !ok || string(b) != `{"streams":[]}` {
+		t.Fatalf("expected cached streams, got %q %v", b, ok)
+	}
+	if _, ok := s.Get("/system/indices/index_sets/stats"); ok {
+		t.Fatalf("live stats must not be cached")
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := s.Get("/streams"); ok {
+		t.Fatalf("expected entry to expire after its TTL")
+	}
+}
+
+func TestZeroTTLDisablesResource(t *testing.T) {
+	t.Parallel()
+
+	s, err := New(t.TempDir(), "https://graylog.example.com/api", map[string]time.Duration{"fields": 0})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	s.Put("/views/fields", []byte(`[]`))
+	if _, ok := s.Get("/views/fields"); ok {
+		t.Fatalf("expected fields caching to be disabled")
+	}
+	if entries, _ := s.Status(); len(entries) != 0 {
+		t.Fatalf("expected nothing written, got %+v", entries)
+	}
+}
+
+func TestEntriesAreKeyedByBaseURL(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	eu, _ := New(dir, "https://eu.example.com/api", nil)
+	us, _ := New(dir, "https://us.example.com/api", nil)
+	eu.Put("/streams", []byte(`{"streams":["eu"]}`))
+	if _, ok := us.Get("/streams"); ok {
+		t.Fatalf("expected other cluster to miss")
+	}
+}
+
+func TestStatusAndClear(t *testing.T) {
+	t.Parallel()
+
+	s, err := New(t.TempDir(), "https://graylog.example.com/api", nil)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	s.Put("/streams", []byte(`{}`))
+	s.Put("/cluster/nodes", []byte(`{}`))
+
+	entries, err := s.Status()
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Resource != "nodes" || entries[1].Resource != "streams" || !entries[1].Fresh {
+		t.Fatalf("unexpected status: %+v", entries)
+	}
+	if entries[1].URL != "https://graylog.example.com/api/streams" {
+		t.Fatalf("unexpected url %q", entries[1].URL)
+	}
+
+	if n, err := s.Clear("streams"); err != nil || n != 1 {
+		t.Fatalf("clear streams: %d %v", n, err)
+	}
+	if _, ok := s.Get("/cluster/nodes"); !ok {
+		t.Fatalf("expected nodes to survive a streams clear")
+	}
+	if n, err := s.Clear(); err != nil || n != 1 {
+		t.Fatalf("clear all: %d %v", n, err)
+	}
+	if _, err := s.Clear("bogus"); err == nil {
+		t.Fatalf("expected error for unknown resource")
+	}
+}
+
+func TestNewRejectsUnknownResource(t *testing.T) {
+	t.Parallel()
+
+	if _, err := New(t.TempDir(), "", map[string]time.Duration{"dashboards": time.Minute}); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,107 @@
+package check
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Status is a Nagios plugin state; its value is the plugin exit code.
+type Status int
+
+const (
+	OK Status = iota
+	Warning
+	Critical
+	Unknown
+)
+
+func (s Status) String() string {
+	switch s {
+	case OK:
+		return "OK"
+	case Warning:
+		return "WARNING"
+	case Critical:
+		return "CRITICAL"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Thresholds alert when a count goes above Warn/Crit, or below them when
+// Below is set. A nil threshold is not checked.
+type Thresholds struct {
+	Warn  *int
+	Crit  *int
+	Below bool
+}
+
+func (t Thresholds) Validate() error {
+	if t.Warn == nil && t.Crit == nil {
+		return fmt.Errorf("set --warn and/or --crit")
+	}
+	if t.Warn == nil || t.Crit == nil {
+		return nil
+	}
+	if !t.Below && *t.Crit < *t.Warn {
+		return fmt.Errorf("--crit (%d) must be >= --warn (%d)", *t.Crit, *t.Warn)
+	}
+	if t.Below && *t.Crit > *t.Warn {
+		return fmt.Errorf("with --below, --crit (%d) must be <= --warn (%d)", *t.Crit, *t.Warn)
+	}
+	return nil
+}
+
+func (t Thresholds) Evaluate(count int) Status {
+	if t.breached(t.Crit, count) {
+		return Critical
+	}
+	if t.breached(t.Warn, count) {
+		return Warning
+	}
+	return OK
+}
+
+func (t Thresholds) breached(limit *int, count int) bool {
+	if limit == nil {
+		return false
+	}
+	if t.Below {
+		return count < *limit
+	}
+	return count > *limit
+}
+
+// Describe renders the thresholds for the status line, e.g. "warn > 10, crit > 50".
+func (t Thresholds) Describe() string {
+	op := ">"
+	if t.Below {
+		op = "<"
+	}
+	var parts []string
+	if t.Warn != nil {
+		parts = append(parts, fmt.Sprintf("warn %s %d", op, *t.Warn))
+	}
+	if t.Crit != nil {
+		parts = append(parts, fmt.Sprintf("crit %s %d", op, *t.Crit))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// PerfData renders the count as Nagios performance data: count=N;warn;crit;0.
+func (t Thresholds) PerfData(count int) string {
+	return fmt.Sprintf("count=%d;%s;%s;0", count, perfThreshold(t.Warn, t.Below), perfThreshold(t.Crit, t.Below))
+}
+
+// perfThreshold uses the Nagios range syntax, where "N" alerts above N and
+// "N:" alerts below N.
+func perfThreshold(limit *int, below bool) string {
+	if limit == nil {
+		return ""
+	}
+	if below {
+		return strconv.Itoa(*limit) + ":"
+	}
+	return strconv.Itoa(*limit)
+}
@@ -0,0 +1,57 @@
+package check
+
+import "testing"
+
+func intp(v int) *int { return &v }
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		th    Thresholds
+		count int
+		want  Status
+	}{
+		{name: "above ok", th: Thresholds{Warn: intp(10), Crit: intp(50)}, count: 10, want: OK},
+		{name: "above warn", th: Thresholds{Warn: intp(10), Crit: intp(50)}, count: 11, want: Warning},
+		{name: "above crit", th: Thresholds{Warn: intp(10), Crit: intp(50)}, count: 51, want: Critical},
+		{name: "crit only", th: Thresholds{Crit: intp(0)}, count: 1, want: Critical},
+		{name: "below ok", th: Thresholds{Warn: intp(100), Crit: intp(10), Below: true}, count: 100, want: OK},
+		{name: "below warn", th: Thresholds{Warn: intp(100), Crit: intp(10), Below: true}, count: 99, want: Warning},
+		{name: "below crit", th: Thresholds{Warn: intp(100), Crit: intp(10), Below: true}, count: 0, want: Critical},
+	}
+	for _, tc := range tests {
+		if got := tc.th.Evaluate(tc.count); got != tc.want {
+			t.Errorf("%s: got %s want %s", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	if err := (Thresholds{}).Validate(); err == nil {
+		t.Fatalf("expected error without thresholds")
+	}
+	if err := (Thresholds{Warn: intp(50), Crit: intp(10)}).Validate(); err == nil {
+		t.Fatalf("expected error for crit below warn")
+	}
+	if err := (Thresholds{Warn: intp(50), Crit: intp(10), Below: true}).Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestPerfData(t *testing.T) {
+	t.Parallel()
+
+	if got := (Thresholds{Warn: intp(10), Crit: intp(50)}).PerfData(7); got != "count=7;10;50;0" {
+		t.Fatalf("unexpected perfdata %q", got)
+	}
+	if got := (Thresholds{Crit: intp(5), Below: true}).PerfData(7); got != "count=7;;5:;0" {
+		t.Fatalf("unexpected perfdata %q", got)
+	}
+	if got := (Thresholds{Warn: intp(10), Crit: intp(50)}).Describe(); got != "warn > 10, crit > 50" {
+		t.Fatalf("unexpected description %q", got)
+	}
+}
@@ -0,0 +1,74 @@
+package checkpoint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// State records how far a sliced export got: every slice outside
+// [NextFrom, NextTo] is fully written and the output file is valid up to
+// OutputOffset bytes. A zero NextTo means To.
+type State struct {
+	Fingerprint  string    `json:"fingerprint"`
+	From         time.Time `json:"from"`
+	To           time.Time `json:"to"`
+	NextFrom     time.Time `json:"next_from"`
+	NextTo       time.Time `json:"next_to"`
+	OutputOffset int64     `json:"output_offset"`
+	Exported     int       `json:"exported"`
+	Done         bool      `json:"done"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func Fingerprint(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+func Load(path string) (*State, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read checkpoint %s: %w", path, err)
+	}
+	var s State
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("parse checkpoint %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// Save writes the state atomically so an interrupted run never leaves a
+// truncated checkpoint behind.
+func Save(path string, s *State) error {
+	s.UpdatedAt = time.Now().UTC()
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal checkpoint: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("write checkpoint %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write checkpoint %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write checkpoint %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("write checkpoint %s: %w", path, err)
+	}
+	return nil
+}
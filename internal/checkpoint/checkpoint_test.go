@@ -0,0 +1,56 @@
+package checkpoint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "export.ckpt")
+	if s, err := Load(path); err != nil || s != nil {
+		t.Fatalf("expected missing checkpoint, got %+v (err %v)", s, err)
+	}
+
+	from := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	want := &State{
+		Fingerprint:  Fingerprint("query", "csv"),
+		From:         from,
+		To:           from.Add(24 * time.Hour),
+		NextFrom:     from.Add(3 * time.Hour),
+		OutputOffset: 1234,
+		Exported:     42,
+	}
+	if err := Save(path, want); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got.Fingerprint != want.Fingerprint || !got.NextFrom.Equal(want.NextFrom) || got.OutputOffset != 1234 || got.Exported != 42 {
+		t.Fatalf("unexpected state: %+v", got)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected only the checkpoint file, found %d entries", len(entries))
+	}
+}
+
+func TestFingerprintDistinguishesParts(t *testing.T) {
+	t.Parallel()
+
+	if Fingerprint("ab", "c") == Fingerprint("a", "bc") {
+		t.Fatalf("expected different fingerprints")
+	}
+	if Fingerprint("a", "b") != Fingerprint("a", "b") {
+		t.Fatalf("expected stable fingerprint")
+	}
+}
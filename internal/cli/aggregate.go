@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dsantic/graylog-cli/internal/graylog"
+	"github.com/dsantic/graylog-cli/internal/output"
+)
+
+func (a *App) newSearchAggregateCmd() *cobra.Command {
+	var groupBy, metrics []string
+	cmd := &cobra.Command{Use: "aggregate", Short: "Group-by and metric aggregations"}
+
+	common := &searchCommon{}
+	bindSearchQueryFlags(cmd, common)
+	cmd.PersistentFlags().StringSliceVar(&groupBy, "group-by", nil, "Group by field[:limit] (repeatable)")
+	cmd.PersistentFlags().StringSliceVar(&metrics, "metric", []string{"count"}, "Metric count|avg:field|max:field|percentile:field:95 (repeatable)")
+
+	cmd.AddCommand(a.newTimerangeCmds(common, func(cmd *cobra.Command, req graylog.SearchMessagesRequest) error {
+		aggReq, err := buildAggregateRequest(req, groupBy, metrics)
+		if err != nil {
+			return err
+		}
+		return a.runAggregate(cmd, aggReq)
+	})...)
+	return cmd
+}
+
+func buildAggregateRequest(req graylog.SearchMessagesRequest, groupBy, metrics []string) (graylog.AggregateRequest, error) {
+	aggReq := graylog.AggregateRequest{
+		Query:     req.Query,
+		Streams:   req.Streams,
+		Timerange: req.Timerange,
+	}
+	for _, spec := range groupBy {
+		g, err := graylog.ParseAggregateGrouping(spec)
+		if err != nil {
+			return graylog.AggregateRequest{}, err
+		}
+		aggReq.GroupBy = append(aggReq.GroupBy, g)
+	}
+	for _, spec := range metrics {
+		m, err := graylog.ParseAggregateMetric(spec)
+		if err != nil {
+			return graylog.AggregateRequest{}, err
+		}
+		aggReq.Metrics = append(aggReq.Metrics, m)
+	}
+	if len(aggReq.Metrics) == 0 {
+		return graylog.AggregateRequest{}, fmt.Errorf("at least one --metric is required")
+	}
+	return aggReq, nil
+}
+
+func (a *App) runAggregate(cmd *cobra.Command, req graylog.AggregateRequest) error {
+	if err := a.mustAuth(); err != nil {
+		return err
+	}
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	if req.Streams, err = graylog.NewResolver(c).StreamIDs(cmd.Context(), req.Streams); err != nil {
+		return err
+	}
+	resp, err := c.Aggregate(cmd.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	if a.runtime.Format == "json" {
+		return output.PrintJSON(cmd.OutOrStdout(), graylog.NormalizeAggregateResponse(resp))
+	}
+	return output.PrintRowsTable(cmd.OutOrStdout(), resp.Schema, resp.DataRows, a.runtime.MaxWidth)
+}
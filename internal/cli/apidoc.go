@@ -0,0 +1,211 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"github.com/dsantic/graylog-cli/internal/apidoc"
+	"github.com/dsantic/graylog-cli/internal/graylog"
+	"github.com/dsantic/graylog-cli/internal/output"
+)
+
+// apiDocPaths are tried in order: OpenAPI documents of current releases, then
+// the Swagger listing behind the API browser of older ones.
+var apiDocPaths = []string{"/openapi.json", "/openapi.yaml", "/api-docs"}
+
+const apiDocAccept = "application/json, application/yaml;q=0.9, */*;q=0.8"
+
+// apiDocConcurrency bounds parallel fetches of Swagger 1.2 declarations.
+const apiDocConcurrency = 8
+
+func (a *App) newAPIEndpointsCmd() *cobra.Command {
+	var filter, docPath string
+	cmd := &cobra.Command{
+		Use:   "endpoints",
+		Short: "List the endpoints described by the server's API browser",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			doc, err := a.loadAPIDoc(cmd.Context(), docPath)
+			if err != nil {
+				return err
+			}
+			list := doc.Filter(filter)
+			if a.runtime.Format == "json" {
+				return output.PrintJSON(cmd.OutOrStdout(), list)
+			}
+			tw := table.NewWriter()
+			tw.AppendHeader(table.Row{"METHOD", "PATH", "SUMMARY"})
+			for _, e := range list {
+				tw.AppendRow(table.Row{e.Method, e.Path, output.FormatCell(e.Summary, a.runtime.MaxWidth)})
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), tw.Render())
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&filter, "filter", "", "Only endpoints whose method, path, summary or tag contains this text")
+	cmd.Flags().StringVar(&docPath, "doc-path", "", "API description path relative to the API base (default: auto-detect)")
+	return cmd
+}
+
+func (a *App) newAPIDescribeCmd() *cobra.Command {
+	var docPath string
+	cmd := &cobra.Command{
+		Use:   "describe <path>",
+		Short: "Show methods, parameters, permissions and body schema of an endpoint",
+		Long:  "Accepts a template (/streams/{streamId}) or a concrete path (/streams/abc).",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			doc, err := a.loadAPIDoc(cmd.Context(), docPath)
+			if err != nil {
+				return err
+			}
+			p := args[0]
+			if base := strings.TrimRight(a.runtime.APIBase, "/"); base != "" && strings.HasPrefix(p, base+"/") {
+				p = strings.TrimPrefix(p, base)
+			}
+			matches := doc.Match(p)
+			if len(matches) == 0 {
+				return fmt.Errorf("no endpoint matches %q (see: graylogctl api endpoints --filter ...)", args[0])
+			}
+			if a.runtime.Format == "json" {
+				return output.PrintJSON(cmd.OutOrStdout(), matches)
+			}
+			for i, e := range matches {
+				if i > 0 {
+					fmt.Fprintln(cmd.OutOrStdout())
+				}
+				if err := printEndpoint(cmd.OutOrStdout(), e, a.runtime.MaxWidth); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&docPath, "doc-path", "", "API description path relative to the API base (default: auto-detect)")
+	return cmd
+}
+
+func printEndpoint(w io.Writer, e apidoc.Endpoint, maxWidth int) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s\n", e.Method, e.Path)
+	if e.Summary != "" {
+		fmt.Fprintf(&b, "  %s\n", e.Summary)
+	}
+	if e.Description != "" && e.Description != e.Summary {
+		fmt.Fprintf(&b, "  %s\n", e.Description)
+	}
+	if len(e.Tags) > 0 {
+		fmt.Fprintf(&b, "Tags: %s\n", strings.Join(e.Tags, ", "))
+	}
+	perms := "not declared"
+	if len(e.Permissions) > 0 {
+		perms = strings.Join(e.Permissions, ", ")
+	}
+	fmt.Fprintf(&b, "Permissions: %s\n", perms)
+
+	if len(e.Parameters) > 0 {
+		tw := table.NewWriter()
+		tw.AppendHeader(table.Row{"PARAMETER", "IN", "TYPE", "REQUIRED", "DESCRIPTION"})
+		for _, p := range e.Parameters {
+			tw.AppendRow(table.Row{p.Name, p.In, p.Type, p.Required, output.FormatCell(p.Description, maxWidth)})
+		}
+		fmt.Fprintln(&b, tw.Render())
+	}
+	if e.BodyType != "" || len(e.Body) > 0 {
+		fmt.Fprintf(&b, "Body: %s\n", e.BodyType)
+		if len(e.Body) > 0 {
+			tw := table.NewWriter()
+			tw.AppendHeader(table.Row{"FIELD", "TYPE", "REQUIRED"})
+			for _, f := range e.Body {
+				tw.AppendRow(table.Row{f.Name, f.Type, f.Required})
+			}
+			fmt.Fprintln(&b, tw.Render())
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// loadAPIDoc downloads and parses the server's API description. Without
+// docPath, the known locations are tried until one exists.
+func (a *App) loadAPIDoc(ctx context.Context, docPath string) (apidoc.Document, error) {
+	if err := a.mustAuth(); err != nil {
+		return apidoc.Document{}, err
+	}
+	c, err := a.client()
+	if err != nil {
+		return apidoc.Document{}, err
+	}
+	candidates := apiDocPaths
+	if docPath != "" {
+		candidates = []string{docPath}
+	}
+	for _, p := range candidates {
+		raw, err := fetchAPIDoc(ctx, c, p)
+		var apiErr *graylog.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound && docPath == "" {
+			continue
+		}
+		if err != nil {
+			return apidoc.Document{}, err
+		}
+		if resources, ok := apidoc.ResourcePaths(raw); ok {
+			return fetchDeclarations(ctx, c, p, resources)
+		}
+		doc, err := apidoc.Parse(raw)
+		if err != nil {
+			return apidoc.Document{}, fmt.Errorf("%s: %w", c.URLFor(p), err)
+		}
+		return doc, nil
+	}
+	return apidoc.Document{}, fmt.Errorf("no API description found under %s (tried %s); set --doc-path", c.URLFor("/"), strings.Join(candidates, ", "))
+}
+
+func fetchAPIDoc(ctx context.Context, c *graylog.Client, p string) (map[string]any, error) {
+	resp, err := c.Call(ctx, graylog.CallRequest{Method: http.MethodGet, Path: p, Accept: apiDocAccept})
+	if err != nil {
+		return nil, err
+	}
+	raw, err := apidoc.Decode(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", c.URLFor(p), err)
+	}
+	return raw, nil
+}
+
+// fetchDeclarations loads every API declaration of a Swagger 1.2 listing.
+func fetchDeclarations(ctx context.Context, c *graylog.Client, listingPath string, resources []string) (apidoc.Document, error) {
+	docs := make([]apidoc.Document, len(resources))
+	errs := make([]error, len(resources))
+	sem := make(chan struct{}, apiDocConcurrency)
+	var wg sync.WaitGroup
+	for i, r := range resources {
+		i, r := i, r
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			raw, err := fetchAPIDoc(ctx, c, strings.TrimRight(listingPath, "/")+"/"+strings.TrimLeft(r, "/"))
+			if err == nil {
+				docs[i], err = apidoc.Parse(raw)
+			}
+			errs[i] = err
+		}()
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			return apidoc.Document{}, fmt.Errorf("API declaration %s: %w", resources[i], err)
+		}
+	}
+	return apidoc.Merge(docs...), nil
+}
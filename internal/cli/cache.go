@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"github.com/dsantic/graylog-cli/internal/cache"
+	"github.com/dsantic/graylog-cli/internal/output"
+)
+
+func (a *App) newCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "cache", Short: "Local metadata cache commands"}
+	cmd.AddCommand(a.newCacheStatusCmd(), a.newCacheClearCmd())
+	return cmd
+}
+
+func (a *App) newCacheStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show cached metadata for the current profile",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			store, err := a.cacheStore(a.runtime.Profile, cacheBase(a.runtime.URL, a.runtime.APIBase), a.runtime.CacheTTL)
+			if err != nil {
+				return err
+			}
+			entries, err := store.Status()
+			if err != nil {
+				return err
+			}
+
+			if a.runtime.Format == "json" {
+				rows := make([]map[string]any, 0, len(entries))
+				for _, e := range entries {
+					rows = append(rows, map[string]any{
+						"resource":    e.Resource,
+						"url":         e.URL,
+						"age_seconds": int(e.Age.Seconds()),
+						"ttl_seconds": int(e.TTL.Seconds()),
+						"bytes":       e.Size,
+						"fresh":       e.Fresh,
+					})
+				}
+				return output.PrintJSON(cmd.OutOrStdout(), rows)
+			}
+
+			tw := table.NewWriter()
+			tw.AppendHeader(table.Row{"RESOURCE", "URL", "AGE", "TTL", "BYTES", "STATE"})
+			for _, e := range entries {
+				state := "expired"
+				if e.Fresh {
+					state = "fresh"
+				}
+				tw.AppendRow(table.Row{e.Resource, e.URL, e.Age.Round(time.Second), e.TTL, e.Size, state})
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), tw.Render())
+			return err
+		},
+	}
+}
+
+func (a *App) newCacheClearCmd() *cobra.Command {
+	var allProfiles bool
+	cmd := &cobra.Command{
+		Use:   "clear [resource...]",
+		Short: "Remove cached metadata (streams, inputs, nodes, fields, index_sets)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if allProfiles {
+				if len(args) > 0 {
+					return fmt.Errorf("resources cannot be combined with --all-profiles")
+				}
+				root, err := cache.RootDir()
+				if err != nil {
+					return err
+				}
+				if err := os.RemoveAll(root); err != nil {
+					return fmt.Errorf("remove cache %s: %w", root, err)
+				}
+				_, err = fmt.Fprintf(cmd.OutOrStdout(), "removed %s\n", root)
+				return err
+			}
+			store, err := a.cacheStore(a.runtime.Profile, cacheBase(a.runtime.URL, a.runtime.APIBase), a.runtime.CacheTTL)
+			if err != nil {
+				return err
+			}
+			n, err := store.Clear(args...)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "removed %d cache entries for profile %q\n", n, a.runtime.Profile)
+			return err
+		},
+	}
+	cmd.Flags().BoolVar(&allProfiles, "all-profiles", false, "Remove the cache of every profile")
+	return cmd
+}
@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dsantic/graylog-cli/internal/check"
+	"github.com/dsantic/graylog-cli/internal/graylog"
+	"github.com/dsantic/graylog-cli/internal/output"
+)
+
+type checkOptions struct {
+	Warn     int
+	Crit     int
+	Below    bool
+	Every    time.Duration
+	Deadline time.Duration
+}
+
+func (a *App) newSearchCheckCmd() *cobra.Command {
+	common := &searchCommon{}
+	opts := &checkOptions{}
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Compare the hit count of a query against thresholds (Nagios exit codes)",
+		Long: "Counts the messages matching --query and prints a Nagios-style status line.\n" +
+			"Exit codes: 0 OK, 1 WARNING, 2 CRITICAL, 3 UNKNOWN.",
+		Annotations: map[string]string{checkAnnotation: "true"},
+		// Without a timerange subcommand there is nothing to check; report
+		// UNKNOWN rather than printing help with exit code 0 (OK).
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return fmt.Errorf("choose a timerange: %s relative|absolute|keyword", cmd.CommandPath())
+		},
+	}
+	bindSearchQueryFlags(cmd, common)
+	cmd.PersistentFlags().IntVar(&opts.Warn, "warn", 0, "Warning threshold")
+	cmd.PersistentFlags().IntVar(&opts.Crit, "crit", 0, "Critical threshold")
+	cmd.PersistentFlags().BoolVar(&opts.Below, "below", false, "Alert when the count drops below the thresholds instead of above")
+	cmd.PersistentFlags().Var(newDurationFlag(&opts.Every, 0), "every", "Re-check at this interval until the status is OK (e.g. 1m)")
+	cmd.PersistentFlags().Var(newDurationFlag(&opts.Deadline, 0), "deadline", "With --every, give up after this long and exit with the last status")
+
+	run := func(cmd *cobra.Command, req graylog.SearchMessagesRequest) error {
+		return checkUnknown(a.runCheck(cmd, opts, req))
+	}
+	cmd.AddCommand(a.newTimerangeCmds(common, run)...)
+	return cmd
+}
+
+func (a *App) runCheck(cmd *cobra.Command, opts *checkOptions, req graylog.SearchMessagesRequest) error {
+	var th check.Thresholds
+	if cmd.Flags().Changed("warn") {
+		th.Warn = &opts.Warn
+	}
+	if cmd.Flags().Changed("crit") {
+		th.Crit = &opts.Crit
+	}
+	th.Below = opts.Below
+	if err := th.Validate(); err != nil {
+		return err
+	}
+	if opts.Every < 0 || opts.Deadline < 0 {
+		return fmt.Errorf("--every and --deadline must be >= 0")
+	}
+	if err := a.mustAuth(); err != nil {
+		return err
+	}
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	if req.Streams, err = graylog.NewResolver(c).StreamIDs(cmd.Context(), req.Streams); err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	var deadline time.Time
+	if opts.Deadline > 0 {
+		deadline = time.Now().Add(opts.Deadline)
+	}
+	for {
+		count, err := c.CountMessages(ctx, req.Query, req.Streams, req.Timerange)
+		if errors.Is(err, graylog.ErrDryRun) {
+			return err
+		}
+		status := check.Unknown
+		if err == nil {
+			status = th.Evaluate(count)
+		}
+		if ctx.Err() != nil {
+			return checkExit(check.Unknown)
+		}
+		if perr := a.printCheck(cmd, th, req, status, count, err); perr != nil {
+			return perr
+		}
+
+		if opts.Every <= 0 || status == check.OK {
+			return checkExit(status)
+		}
+		if !deadline.IsZero() && !time.Now().Add(opts.Every).Before(deadline) {
+			return checkExit(status)
+		}
+		select {
+		case <-ctx.Done():
+			return checkExit(status)
+		case <-time.After(opts.Every):
+		}
+	}
+}
+
+// checkAnnotation marks the check command; errors from it or its
+// subcommands, including flag and config errors, exit with UNKNOWN.
+const checkAnnotation = "graylogctl/check"
+
+func isCheckCmd(cmd *cobra.Command) bool {
+	for ; cmd != nil; cmd = cmd.Parent() {
+		if cmd.Annotations[checkAnnotation] != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkUnknown turns any error that is not already a check result into an
+// UNKNOWN exit.
+func checkUnknown(err error) error {
+	var exitErr *ExitError
+	if err == nil || errors.As(err, &exitErr) || errors.Is(err, graylog.ErrDryRun) {
+		return err
+	}
+	return &ExitError{Code: int(check.Unknown), Err: fmt.Errorf("UNKNOWN - %w", err)}
+}
+
+func checkExit(status check.Status) error {
+	if status == check.OK {
+		return nil
+	}
+	return &ExitError{Code: int(status)}
+}
+
+func (a *App) printCheck(cmd *cobra.Command, th check.Thresholds, req graylog.SearchMessagesRequest, status check.Status, count int, checkErr error) error {
+	w := cmd.OutOrStdout()
+	if a.runtime.Format == "json" {
+		obj := map[string]any{
+			"status":     status.String(),
+			"code":       int(status),
+			"query":      req.Query,
+			"timerange":  req.Timerange,
+			"warn":       th.Warn,
+			"crit":       th.Crit,
+			"below":      th.Below,
+			"checked_at": time.Now().UTC().Format(graylog.TimestampLayout),
+		}
+		if checkErr != nil {
+			obj["error"] = checkErr.Error()
+		} else {
+			obj["count"] = count
+		}
+		return output.PrintJSONLine(w, obj)
+	}
+	if checkErr != nil {
+		_, err := fmt.Fprintf(w, "%s - %v\n", status, checkErr)
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%s - %d messages for %q over %s (%s) | %s\n",
+		status, count, req.Query, describeTimerange(req.Timerange), th.Describe(), th.PerfData(count))
+	return err
+}
+
+func describeTimerange(tr graylog.SearchTimerange) string {
+	switch tr.Type {
+	case "relative":
+		return "last " + (time.Duration(tr.Range) * time.Second).String()
+	case "absolute":
+		return tr.From + " to " + tr.To
+	default:
+		return tr.Keyword
+	}
+}
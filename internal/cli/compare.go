@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"github.com/dsantic/graylog-cli/internal/graylog"
+	"github.com/dsantic/graylog-cli/internal/output"
+	"github.com/dsantic/graylog-cli/internal/timeexpr"
+)
+
+type compareOptions struct {
+	Window   time.Duration
+	Baseline string
+	At       string
+	By       string
+	Top      int
+	Values   int
+}
+
+type compareWindow struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Count int    `json:"count"`
+}
+
+func (a *App) newSearchCompareCmd() *cobra.Command {
+	common := &searchCommon{}
+	opts := &compareOptions{}
+	cmd := &cobra.Command{
+		Use:   "compare",
+		Short: "Compare a query's hits in the current window against a baseline window",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return a.runCompare(cmd, common, opts)
+		},
+	}
+	bindSearchQueryFlags(cmd, common)
+	cmd.Flags().Var(newDurationFlag(&opts.Window, time.Hour), "window", "Length of both windows (e.g. 15m, 1h)")
+	cmd.Flags().StringVar(&opts.Baseline, "baseline", "24h-ago", "How far back the baseline window is shifted (e.g. 24h-ago, 7d-ago)")
+	cmd.Flags().StringVar(&opts.At, "at", "now", "End of the current window")
+	cmd.Flags().StringVar(&opts.By, "by", "", "Break the counts down by this field")
+	cmd.Flags().IntVar(&opts.Top, "top", 10, "With --by, show the N values that changed most")
+	cmd.Flags().IntVar(&opts.Values, "values", 100, "With --by, number of most frequent values fetched per window")
+	return cmd
+}
+
+// parseBaselineOffset accepts "24h", "24h-ago", "1d ago" and "-7d".
+func parseBaselineOffset(s string) (time.Duration, error) {
+	v := strings.TrimSpace(strings.ToLower(s))
+	v = strings.TrimSpace(strings.TrimSuffix(v, "ago"))
+	v = strings.TrimPrefix(strings.TrimSuffix(v, "-"), "-")
+	d, err := timeexpr.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("--baseline: %w", err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("--baseline must be > 0")
+	}
+	return d, nil
+}
+
+func (a *App) runCompare(cmd *cobra.Command, common *searchCommon, opts *compareOptions) error {
+	if opts.Window <= 0 {
+		return fmt.Errorf("--window must be > 0")
+	}
+	offset, err := parseBaselineOffset(opts.Baseline)
+	if err != nil {
+		return err
+	}
+	if opts.By != "" && (opts.Top <= 0 || opts.Values <= 0) {
+		return fmt.Errorf("--top and --values must be > 0")
+	}
+	end, err := timeexpr.ParseTime(opts.At, time.Now())
+	if err != nil {
+		return fmt.Errorf("--at: %w", err)
+	}
+	current := graylog.AbsoluteTimerange(end.Add(-opts.Window), end)
+	baseline := graylog.AbsoluteTimerange(end.Add(-offset-opts.Window), end.Add(-offset))
+
+	if err := a.mustAuth(); err != nil {
+		return err
+	}
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	ctx := cmd.Context()
+	query := strings.TrimSpace(common.Query)
+	streams, err := graylog.NewResolver(c).StreamIDs(ctx, common.Streams)
+	if err != nil {
+		return err
+	}
+
+	cur := compareWindow{From: current.From, To: current.To}
+	if cur.Count, err = c.CountMessages(ctx, query, streams, current); err != nil {
+		return fmt.Errorf("count current window: %w", err)
+	}
+	base := compareWindow{From: baseline.From, To: baseline.To}
+	if base.Count, err = c.CountMessages(ctx, query, streams, baseline); err != nil {
+		return fmt.Errorf("count baseline window: %w", err)
+	}
+
+	var deltas []graylog.CountDelta
+	if opts.By != "" {
+		if deltas, err = c.CompareCountsBy(ctx, query, streams, current, baseline, opts.By, opts.Values); err != nil {
+			return err
+		}
+		if len(deltas) > opts.Top {
+			deltas = deltas[:opts.Top]
+		}
+	}
+
+	change := graylog.PercentChange(cur.Count, base.Count)
+	if a.runtime.Format == "json" {
+		doc := map[string]any{
+			"query":          query,
+			"current":        cur,
+			"baseline":       base,
+			"delta":          cur.Count - base.Count,
+			"change_percent": change,
+		}
+		if opts.By != "" {
+			doc["by"] = map[string]any{"field": opts.By, "values": deltas}
+		}
+		return output.PrintJSON(cmd.OutOrStdout(), doc)
+	}
+
+	w := cmd.OutOrStdout()
+	tw := table.NewWriter()
+	tw.AppendHeader(table.Row{"WINDOW", "FROM", "TO", "COUNT"})
+	tw.AppendRow(table.Row{"current", cur.From, cur.To, cur.Count})
+	tw.AppendRow(table.Row{"baseline", base.From, base.To, base.Count})
+	if _, err := fmt.Fprintln(w, tw.Render()); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "change: %s (%+d)\n", formatChange(change, cur.Count), cur.Count-base.Count); err != nil {
+		return err
+	}
+	if opts.By == "" {
+		return nil
+	}
+
+	tw = table.NewWriter()
+	tw.AppendHeader(table.Row{strings.ToUpper(opts.By), "CURRENT", "BASELINE", "DELTA", "CHANGE"})
+	for _, d := range deltas {
+		tw.AppendRow(table.Row{output.FormatCell(d.Value, a.runtime.MaxWidth), d.Current, d.Baseline, fmt.Sprintf("%+d", d.Delta), formatChange(d.Change, d.Current)})
+	}
+	_, err = fmt.Fprintln(w, tw.Render())
+	return err
+}
+
+func formatChange(change *float64, current int) string {
+	if change != nil {
+		return fmt.Sprintf("%+.1f%%", *change)
+	}
+	if current > 0 {
+		return "new"
+	}
+	return "n/a"
+}
@@ -0,0 +1,206 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"github.com/dsantic/graylog-cli/internal/graylog"
+	"github.com/dsantic/graylog-cli/internal/output"
+)
+
+type contextOptions struct {
+	Index   string
+	Before  int
+	After   int
+	Same    []string
+	Window  time.Duration
+	Fields  string
+	Query   string
+	Streams []string
+}
+
+func (a *App) newSearchContextCmd() *cobra.Command {
+	opts := &contextOptions{}
+	cmd := &cobra.Command{
+		Use:   "context <message-id>",
+		Short: "Show messages surrounding a message from the same source",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Before < 0 || opts.After < 0 {
+				return fmt.Errorf("--before and --after must be >= 0")
+			}
+			if opts.Window <= 0 {
+				return fmt.Errorf("--window must be > 0")
+			}
+			if len(opts.Same) == 0 {
+				return fmt.Errorf("--same needs at least one field")
+			}
+			if err := a.mustAuth(); err != nil {
+				return err
+			}
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			return a.runContext(cmd, c, strings.TrimSpace(args[0]), opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.Index, "index", "", "Index holding the message (looked up by _id when empty)")
+	cmd.Flags().IntVar(&opts.Before, "before", 50, "Messages to show before the anchor")
+	cmd.Flags().IntVar(&opts.After, "after", 50, "Messages to show after the anchor")
+	cmd.Flags().StringSliceVar(&opts.Same, "same", []string{"source"}, "Fields whose anchor values surrounding messages must share")
+	cmd.Flags().Var(newDurationFlag(&opts.Window, time.Hour), "window", "How far before and after the anchor to look")
+	cmd.Flags().StringVar(&opts.Fields, "fields", "timestamp,source,message", "Comma-separated fields")
+	cmd.Flags().StringVar(&opts.Query, "query", "", "Additional query the surrounding messages must match")
+	cmd.Flags().StringSliceVar(&opts.Streams, "stream", nil, "Restrict surrounding messages to stream id or title (repeatable)")
+	return cmd
+}
+
+func (a *App) runContext(cmd *cobra.Command, c *graylog.Client, id string, opts *contextOptions) error {
+	var anchor graylog.MessageResponse
+	var err error
+	if opts.Index != "" {
+		anchor, err = c.GetMessage(cmd.Context(), opts.Index, id)
+	} else {
+		anchor, err = c.FindMessage(cmd.Context(), id)
+	}
+	if err != nil {
+		return err
+	}
+	ts, ok := graylog.ParseTimestamp(anchor.Message["timestamp"])
+	if !ok {
+		return fmt.Errorf("message %s has no usable timestamp (%v)", id, anchor.Message["timestamp"])
+	}
+
+	clauses := make([]string, 0, len(opts.Same)+1)
+	for _, f := range opts.Same {
+		v, ok := anchor.Message[f]
+		if !ok || v == nil {
+			return fmt.Errorf("message %s has no field %q to match on", id, f)
+		}
+		clauses = append(clauses, f+":"+quoteQueryValue(v))
+	}
+	if q := strings.TrimSpace(opts.Query); q != "" {
+		clauses = append(clauses, "("+q+")")
+	}
+
+	streamIDs, err := graylog.NewResolver(c).StreamIDs(cmd.Context(), opts.Streams)
+	if err != nil {
+		return err
+	}
+	shown := parseFields(opts.Fields)
+	if len(shown) == 0 {
+		shown = []string{"timestamp", "source", "message"}
+	}
+	base := graylog.SearchMessagesRequest{
+		Query:   strings.Join(clauses, " AND "),
+		Fields:  withMessageIDFields(shown),
+		Streams: streamIDs,
+		Sort:    "timestamp",
+	}
+	seen := map[string]bool{id: true}
+
+	before := base
+	before.Size = opts.Before + 1
+	before.SortOrder = "desc"
+	before.Timerange = graylog.AbsoluteTimerange(ts.Add(-opts.Window), ts)
+	preceding, err := searchContextRows(cmd, c, before, opts.Before, seen)
+	if err != nil {
+		return err
+	}
+	for i, j := 0, len(preceding)-1; i < j; i, j = i+1, j-1 {
+		preceding[i], preceding[j] = preceding[j], preceding[i]
+	}
+
+	after := base
+	after.Size = opts.After + 1
+	after.SortOrder = "asc"
+	after.Timerange = graylog.AbsoluteTimerange(ts, ts.Add(opts.Window))
+	following, err := searchContextRows(cmd, c, after, opts.After, seen)
+	if err != nil {
+		return err
+	}
+
+	rows := make([]map[string]any, 0, len(preceding)+len(following)+1)
+	rows = append(rows, preceding...)
+	rows = append(rows, anchor.Message)
+	rows = append(rows, following...)
+	anchorAt := len(preceding)
+
+	if a.runtime.Format == "json" {
+		out := make([]map[string]any, 0, len(rows))
+		for i, row := range rows {
+			obj := make(map[string]any, len(shown)+1)
+			for _, f := range shown {
+				obj[f] = row[f]
+			}
+			if i == anchorAt {
+				obj["_anchor"] = true
+			}
+			out = append(out, obj)
+		}
+		return output.PrintJSON(cmd.OutOrStdout(), map[string]any{"anchor": id, "index": anchor.Index, "rows": out})
+	}
+
+	tw := table.NewWriter()
+	header := table.Row{""}
+	for _, f := range shown {
+		header = append(header, f)
+	}
+	tw.AppendHeader(header)
+	for i, row := range rows {
+		marker := ""
+		if i == anchorAt {
+			marker = ">>"
+		}
+		r := table.Row{marker}
+		for _, f := range shown {
+			r = append(r, output.FormatCell(row[f], a.runtime.MaxWidth))
+		}
+		if i == anchorAt {
+			tw.AppendSeparator()
+			tw.AppendRow(r)
+			tw.AppendSeparator()
+			continue
+		}
+		tw.AppendRow(r)
+	}
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), tw.Render())
+	return err
+}
+
+func searchContextRows(cmd *cobra.Command, c *graylog.Client, req graylog.SearchMessagesRequest, limit int, seen map[string]bool) ([]map[string]any, error) {
+	if limit == 0 {
+		return nil, nil
+	}
+	resp, err := c.SearchMessages(cmd.Context(), req)
+	if err != nil {
+		return nil, err
+	}
+	var rows []map[string]any
+	for _, row := range graylog.NormalizeSearchResponse(resp).Rows {
+		id := messageID(row)
+		if id != "" && seen[id] {
+			continue
+		}
+		if len(rows) == limit {
+			break
+		}
+		if id != "" {
+			seen[id] = true
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func quoteQueryValue(v any) string {
+	s := fmt.Sprintf("%v", v)
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
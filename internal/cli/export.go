@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dsantic/graylog-cli/internal/checkpoint"
+	"github.com/dsantic/graylog-cli/internal/export"
+	"github.com/dsantic/graylog-cli/internal/graylog"
+	"github.com/dsantic/graylog-cli/internal/output"
+)
+
+type exportOptions struct {
+	Output     string
+	Format     string
+	Limit      int
+	ChunkSize  int
+	Checkpoint string
+	Slice      time.Duration
+}
+
+func (a *App) newSearchExportCmd() *cobra.Command {
+	opts := &exportOptions{}
+	cmd := &cobra.Command{Use: "export", Short: "Stream messages to CSV or NDJSON via the views export API"}
+
+	common := &searchCommon{}
+	bindSearchQueryFlags(cmd, common)
+	cmd.PersistentFlags().StringVar(&common.Fields, "fields", "timestamp,source,message", "Comma-separated fields")
+	cmd.PersistentFlags().StringVar(&common.Sort, "sort", "", "Sort field")
+	cmd.PersistentFlags().StringVar(&common.SortOrder, "sort-order", "desc", "Sort order asc|desc")
+	cmd.PersistentFlags().StringVarP(&opts.Output, "output", "o", "-", "Output file ('-' for stdout)")
+	cmd.PersistentFlags().StringVar(&opts.Format, "output-format", "csv", "Export format: csv|ndjson")
+	cmd.PersistentFlags().IntVar(&opts.Limit, "limit", 0, "Maximum messages to export (0 for no limit)")
+	cmd.PersistentFlags().IntVar(&opts.ChunkSize, "chunk-size", 1000, "Messages per chunk requested from Graylog")
+	cmd.PersistentFlags().StringVar(&opts.Checkpoint, "checkpoint", "", "Record progress in FILE and resume from it when re-run")
+	cmd.PersistentFlags().Var(newDurationFlag(&opts.Slice, 0), "slice", "Export in time slices of this size (default 1h with --checkpoint)")
+
+	cmd.AddCommand(a.newTimerangeCmds(common, func(cmd *cobra.Command, req graylog.SearchMessagesRequest) error {
+		return a.runExport(cmd, req, opts)
+	})...)
+	return cmd
+}
+
+func (a *App) runExport(cmd *cobra.Command, req graylog.SearchMessagesRequest, opts *exportOptions) error {
+	opts.Format = strings.ToLower(strings.TrimSpace(opts.Format))
+	if opts.Format != "csv" && opts.Format != "ndjson" {
+		return fmt.Errorf("unsupported --output-format %q (use csv|ndjson)", opts.Format)
+	}
+	if opts.Limit < 0 || opts.ChunkSize <= 0 {
+		return fmt.Errorf("--limit must be >= 0 and --chunk-size > 0")
+	}
+	if opts.Slice < 0 {
+		return fmt.Errorf("--slice must be >= 0")
+	}
+	if opts.Checkpoint != "" && opts.Output == "-" {
+		return fmt.Errorf("--checkpoint requires --output FILE")
+	}
+	if err := a.mustAuth(); err != nil {
+		return err
+	}
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	if req.Streams, err = graylog.NewResolver(c).StreamIDs(cmd.Context(), req.Streams); err != nil {
+		return err
+	}
+
+	exportReq := graylog.NewExportRequest(req)
+	exportReq.Limit = opts.Limit
+	exportReq.ChunkSize = opts.ChunkSize
+	if opts.Checkpoint != "" || opts.Slice > 0 {
+		return a.runSlicedExport(cmd, c, req, exportReq, opts)
+	}
+
+	body, err := c.ExportMessages(cmd.Context(), exportReq)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	var dst io.Writer = cmd.OutOrStdout()
+	if opts.Output != "-" {
+		f, err := os.Create(opts.Output)
+		if err != nil {
+			return fmt.Errorf("create output %s: %w", opts.Output, err)
+		}
+		defer f.Close()
+		dst = f
+	}
+	bw := bufio.NewWriter(dst)
+
+	n, err := output.CopyCSVExport(bw, body, opts.Format, true)
+	if flushErr := bw.Flush(); err == nil && flushErr != nil {
+		err = fmt.Errorf("write output: %w", flushErr)
+	}
+	if err != nil {
+		return err
+	}
+	if opts.Output != "-" {
+		fmt.Fprintf(cmd.ErrOrStderr(), "exported %d messages to %s\n", n, opts.Output)
+	}
+	return nil
+}
+
+func (a *App) runSlicedExport(cmd *cobra.Command, c *graylog.Client, req graylog.SearchMessagesRequest, exportReq graylog.ExportRequest, opts *exportOptions) error {
+	slice := opts.Slice
+	if slice == 0 {
+		slice = time.Hour
+	}
+	outputPath := opts.Output
+	if outputPath != "-" {
+		abs, err := filepath.Abs(outputPath)
+		if err != nil {
+			return fmt.Errorf("resolve output %s: %w", outputPath, err)
+		}
+		outputPath = abs
+	}
+	// Slices are walked in timestamp order so the file stays sorted across
+	// slice boundaries; other sort fields only order rows within a slice.
+	descending := false
+	if req.Sort == "" || req.Sort == "timestamp" {
+		order := strings.ToLower(req.SortOrder)
+		if order != "asc" {
+			order = "desc"
+		}
+		descending = order == "desc"
+		exportReq.Sort = []graylog.ExportSort{{Field: "timestamp", Order: strings.ToUpper(order)}}
+	}
+	tr := req.Timerange
+	fingerprint := checkpoint.Fingerprint(
+		req.Query, strings.Join(req.Streams, ","), strings.Join(req.Fields, ","), req.Sort, req.SortOrder,
+		fmt.Sprintf("%s|%d|%s|%s|%s", tr.Type, tr.Range, tr.From, tr.To, tr.Keyword),
+		opts.Format, slice.String(), strconv.Itoa(opts.Limit), outputPath, fmt.Sprintf("descending=%t", descending),
+	)
+
+	var state *checkpoint.State
+	if opts.Checkpoint != "" {
+		loaded, err := checkpoint.Load(opts.Checkpoint)
+		if err != nil {
+			return err
+		}
+		if loaded != nil && loaded.Fingerprint != fingerprint {
+			return fmt.Errorf("checkpoint %s belongs to a different export; remove it to start over", opts.Checkpoint)
+		}
+		state = loaded
+	}
+	resumed := state != nil
+	if state == nil {
+		from, to, err := tr.Bounds(time.Now())
+		if err != nil {
+			return fmt.Errorf("sliced export needs a relative or absolute timerange: %w", err)
+		}
+		state = &checkpoint.State{Fingerprint: fingerprint, From: from, To: to, NextFrom: from}
+	}
+	if state.Done {
+		fmt.Fprintf(cmd.ErrOrStderr(), "export already complete per %s (%d messages)\n", opts.Checkpoint, state.Exported)
+		return nil
+	}
+
+	if resumed {
+		at := state.NextFrom
+		if descending && !state.NextTo.IsZero() {
+			at = state.NextTo
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "resuming export at %s (%d messages, %d bytes written)\n",
+			at.Format(graylog.TimestampLayout), state.Exported, state.OutputOffset)
+	}
+
+	sliced := &export.Sliced{
+		Request:    exportReq,
+		State:      state,
+		Resumed:    resumed,
+		Slice:      slice,
+		Descending: descending,
+		Format:     opts.Format,
+		Limit:      opts.Limit,
+		Output:     opts.Output,
+		Stdout:     cmd.OutOrStdout(),
+		Checkpoint: opts.Checkpoint,
+	}
+	if err := sliced.Run(cmd.Context(), c); err != nil {
+		return err
+	}
+	if opts.Output != "-" {
+		fmt.Fprintf(cmd.ErrOrStderr(), "exported %d messages to %s\n", state.Exported, opts.Output)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dsantic/graylog-cli/internal/graylog"
+)
+
+const profileColumn = "_profile"
+
+func (a *App) fanoutProfiles(common *searchCommon) ([]string, error) {
+	if !common.AllProfiles {
+		names := make([]string, 0, len(common.Profiles))
+		for _, p := range common.Profiles {
+			if p = strings.TrimSpace(p); p != "" && !containsString(names, p) {
+				names = append(names, p)
+			}
+		}
+		return names, nil
+	}
+	names := make([]string, 0, len(a.cfg.Profiles))
+	for name, p := range a.cfg.Profiles {
+		if strings.TrimSpace(p.URL) != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("--all-profiles: no profile in config has a url")
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// runFanoutSearch runs the same search against several profiles concurrently.
+// Failing clusters are reported but do not abort the search unless all fail.
+func (a *App) runFanoutSearch(cmd *cobra.Command, common *searchCommon, req graylog.SearchMessagesRequest) error {
+	if common.Split {
+		return fmt.Errorf("--split cannot be combined with --profiles/--all-profiles")
+	}
+	names, err := a.fanoutProfiles(common)
+	if err != nil {
+		return err
+	}
+
+	results := make(map[string]graylog.SearchMessagesResponse, len(names))
+	failures := map[string]string{}
+	dryRun := false
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, name := range names {
+		name := name
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := a.searchProfile(cmd, name, common, req)
+			mu.Lock()
+			defer mu.Unlock()
+			if errors.Is(err, graylog.ErrDryRun) {
+				dryRun = true
+				return
+			}
+			if err != nil {
+				failures[name] = err.Error()
+				return
+			}
+			results[name] = resp
+		}()
+	}
+	wg.Wait()
+	if dryRun {
+		return graylog.ErrDryRun
+	}
+
+	ok := make([]string, 0, len(results))
+	for _, name := range names {
+		if msg, failed := failures[name]; failed {
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: profile %s: %s\n", name, msg)
+			continue
+		}
+		ok = append(ok, name)
+	}
+	if len(ok) == 0 {
+		return fmt.Errorf("search failed on all %d profiles", len(names))
+	}
+
+	sortField := req.Sort
+	if sortField == "" {
+		sortField = "timestamp"
+	}
+	merged := graylog.MergeSearchResponses(ok, results, profileColumn, sortField, req.SortOrder != "asc")
+	merged.Metadata = map[string]any{"profiles": ok}
+	if len(failures) > 0 {
+		merged.Metadata["errors"] = failures
+	}
+
+	stream := a.newPageSink(cmd, common)
+	if err := stream.WritePage(merged); err != nil {
+		return err
+	}
+	return stream.Close()
+}
+
+func (a *App) searchProfile(cmd *cobra.Command, name string, common *searchCommon, req graylog.SearchMessagesRequest) (graylog.SearchMessagesResponse, error) {
+	c, err := a.profileClient(name)
+	if err != nil {
+		return graylog.SearchMessagesResponse{}, err
+	}
+	resolver := graylog.NewResolver(c)
+	resolver.Warn = cmd.ErrOrStderr()
+	if req.Streams, err = resolver.StreamIDs(cmd.Context(), req.Streams); err != nil {
+		return graylog.SearchMessagesResponse{}, err
+	}
+	if common.Validate {
+		tr := req.Timerange
+		v, err := c.ValidateQuery(cmd.Context(), graylog.QueryValidationRequest{Query: req.Query, Timerange: &tr, Streams: req.Streams})
+		if err != nil {
+			return graylog.SearchMessagesResponse{}, fmt.Errorf("validate query: %w", err)
+		}
+		if v.Invalid() {
+			return graylog.SearchMessagesResponse{}, fmt.Errorf("query is invalid; not running search")
+		}
+	}
+	var resp graylog.SearchMessagesResponse
+	if !common.All && common.MaxResults == 0 {
+		resp, err = c.SearchMessages(cmd.Context(), req)
+	} else {
+		_, err = c.SearchMessagesPages(cmd.Context(), req, common.MaxResults, func(page graylog.SearchMessagesResponse) error {
+			if resp.Schema == nil {
+				resp.Schema = page.Schema
+			}
+			resp.DataRows = append(resp.DataRows, page.DataRows...)
+			return nil
+		})
+	}
+	if err != nil {
+		return resp, err
+	}
+	if common.ResolveNames {
+		err = resolver.EnrichSearchResponse(cmd.Context(), &resp)
+	}
+	return resp, err
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"github.com/dsantic/graylog-cli/internal/graylog"
+	"github.com/dsantic/graylog-cli/internal/output"
+)
+
+func (a *App) newSearchFieldsCmd() *cobra.Command {
+	var (
+		streams []string
+		filter  string
+	)
+	cmd := &cobra.Command{
+		Use:   "fields",
+		Short: "List available message fields and their types",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := a.mustAuth(); err != nil {
+				return err
+			}
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			streamIDs, err := graylog.NewResolver(c).StreamIDs(cmd.Context(), streams)
+			if err != nil {
+				return err
+			}
+			fields, err := c.ListFields(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if len(streamIDs) > 0 && !fieldsHaveStreams(fields) {
+				fmt.Fprintln(cmd.ErrOrStderr(), "warning: Graylog did not report which streams fields belong to (GET /api/system/fields); --stream is ignored")
+			}
+			fields = graylog.FilterFields(fields, streamIDs, strings.TrimSpace(filter))
+
+			if a.runtime.Format == "json" {
+				return output.PrintJSON(cmd.OutOrStdout(), fields)
+			}
+			tw := table.NewWriter()
+			tw.AppendHeader(table.Row{"NAME", "TYPE", "PROPERTIES"})
+			for _, f := range fields {
+				tw.AppendRow(table.Row{f.Name, f.Type.Type, strings.Join(f.Type.Properties, ",")})
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), tw.Render())
+			return err
+		},
+	}
+	cmd.Flags().StringSliceVar(&streams, "stream", nil, "Only fields seen in stream id or title (repeatable)")
+	cmd.Flags().StringVar(&filter, "filter", "", "Case-insensitive substring match on field name")
+	return cmd
+}
+
+// fieldsHaveStreams reports whether any field carries stream ids; the
+// legacy /system/fields fallback returns names only.
+func fieldsHaveStreams(fields []graylog.FieldType) bool {
+	for _, f := range fields {
+		if len(f.Streams) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// warnUnknownFields reports requested fields Graylog does not know about. It
+// is best effort: lookup failures are silently ignored.
+func (a *App) warnUnknownFields(cmd *cobra.Command, c *graylog.Client, requested []string) {
+	known, err := c.ListFields(cmd.Context())
+	if err != nil || len(known) == 0 {
+		return
+	}
+	names := make(map[string]bool, len(known))
+	for _, f := range known {
+		names[f.Name] = true
+	}
+	for _, f := range requested {
+		if !names[f] && f != "_id" {
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: field %q does not exist (see graylogctl search fields)\n", f)
+		}
+	}
+}
@@ -1,12 +1,97 @@
 package cli
 
-import "github.com/dsantic/graylog-cli/internal/graylog"
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dsantic/graylog-cli/internal/cache"
+	"github.com/dsantic/graylog-cli/internal/config"
+	"github.com/dsantic/graylog-cli/internal/graylog"
+)
 
 func (a *App) loginClient() (*graylog.Client, error) {
 	return graylog.NewClient(graylog.ClientConfig{
-		BaseURL:  a.runtime.URL,
-		APIBase:  a.runtime.APIBase,
-		Insecure: a.runtime.Insecure,
-		Timeout:  a.runtime.Timeout,
+		BaseURL:   a.runtime.URL,
+		APIBase:   a.runtime.APIBase,
+		Insecure:  a.runtime.Insecure,
+		Timeout:   a.runtime.Timeout,
+		DryRun:    a.dryRunMode(),
+		DryRunOut: a.stdout,
+		Retry:     graylog.RetryPolicy{MaxRetries: a.runtime.Retries},
+		RateLimit: a.runtime.RateLimit,
+		Trace:     a.traceLevel(),
+		TraceOut:  a.stderr,
+	})
+}
+
+// profileClient builds a client from a config profile alone, for commands that
+// talk to several clusters at once. Retries and rate limit come from the
+// profile unless set by flag or environment.
+func (a *App) profileClient(name string) (*graylog.Client, error) {
+	p, ok := a.cfg.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %q not found in config", name)
+	}
+	if p.Auth.Token == "" && p.Auth.Session == "" {
+		return nil, fmt.Errorf("profile %q has no token or session configured", name)
+	}
+	apiBase := p.APIBase
+	if apiBase == "" {
+		apiBase = config.DefaultAPIBase
+	}
+	ttls, err := config.ParseCacheTTL(p.CacheTTL)
+	if err != nil {
+		return nil, fmt.Errorf("profile %q: %w", name, err)
+	}
+	retries, rateLimit := a.runtime.ProfileRetries(p), a.runtime.ProfileRateLimit(p)
+	if retries < 0 || rateLimit < 0 {
+		return nil, fmt.Errorf("profile %q: retries and rate_limit must be >= 0", name)
+	}
+	rc, err := a.responseCache(name, cacheBase(p.URL, apiBase), ttls)
+	if err != nil {
+		return nil, err
+	}
+	return graylog.NewClient(graylog.ClientConfig{
+		BaseURL:   p.URL,
+		APIBase:   apiBase,
+		Token:     p.Auth.Token,
+		Session:   p.Auth.Session,
+		Insecure:  p.Insecure,
+		Timeout:   a.runtime.Timeout,
+		Cache:     rc,
+		DryRun:    a.dryRunMode(),
+		DryRunOut: a.stdout,
+		Retry:     graylog.RetryPolicy{MaxRetries: retries},
+		RateLimit: rateLimit,
+		Trace:     a.traceLevel(),
+		TraceOut:  a.stderr,
 	})
 }
+
+// responseCache returns the on-disk metadata cache for a profile, or nil when
+// --no-cache is set.
+func (a *App) responseCache(profile, baseURL string, ttls map[string]time.Duration) (graylog.ResponseCache, error) {
+	if a.runtime.NoCache {
+		return nil, nil
+	}
+	store, err := a.cacheStore(profile, baseURL, ttls)
+	if err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// cacheBase keys cache entries by server and API base so a profile pointed at
+// another cluster never sees the previous cluster's metadata.
+func cacheBase(baseURL, apiBase string) string {
+	return strings.TrimRight(baseURL, "/") + "/" + strings.Trim(apiBase, "/")
+}
+
+func (a *App) cacheStore(profile, baseURL string, ttls map[string]time.Duration) (*cache.Store, error) {
+	dir, err := cache.ProfileDir(profile)
+	if err != nil {
+		return nil, err
+	}
+	return cache.New(dir, baseURL, ttls)
+}
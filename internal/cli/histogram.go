@@ -0,0 +1,172 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"github.com/dsantic/graylog-cli/internal/graylog"
+	"github.com/dsantic/graylog-cli/internal/output"
+)
+
+const (
+	histogramMaxBuckets  = 60
+	histogramConcurrency = 4
+	histogramBarWidth    = 40
+)
+
+var histogramIntervals = []time.Duration{
+	time.Second, 5 * time.Second, 10 * time.Second, 30 * time.Second,
+	time.Minute, 5 * time.Minute, 10 * time.Minute, 15 * time.Minute, 30 * time.Minute,
+	time.Hour, 3 * time.Hour, 6 * time.Hour, 12 * time.Hour, 24 * time.Hour, 7 * 24 * time.Hour,
+}
+
+type histogramBucket struct {
+	Bucket string `json:"bucket"`
+	Count  int    `json:"count"`
+}
+
+func (a *App) newSearchHistogramCmd() *cobra.Command {
+	var interval time.Duration
+	cmd := &cobra.Command{Use: "histogram", Short: "Message counts per time bucket"}
+
+	common := &searchCommon{}
+	bindSearchQueryFlags(cmd, common)
+	cmd.PersistentFlags().Var(newDurationFlag(&interval, 0), "interval", "Bucket size (e.g. 1m, 1h, 1d); chosen from the range when 0")
+
+	cmd.AddCommand(a.newBoundedTimerangeCmds(common, func(cmd *cobra.Command, req graylog.SearchMessagesRequest) error {
+		if interval < 0 {
+			return fmt.Errorf("--interval must be >= 0")
+		}
+		return a.runHistogram(cmd, req, interval)
+	})...)
+	return cmd
+}
+
+func (a *App) runHistogram(cmd *cobra.Command, req graylog.SearchMessagesRequest, interval time.Duration) error {
+	from, to, err := req.Timerange.Bounds(time.Now())
+	if err != nil {
+		return fmt.Errorf("histogram needs a relative or absolute timerange: %w", err)
+	}
+	if interval == 0 {
+		interval = histogramInterval(to.Sub(from))
+	}
+	ranges := graylog.SplitTimerange(from, to, interval)
+	if len(ranges) > 10*histogramMaxBuckets {
+		return fmt.Errorf("--interval %s yields %d buckets; use a larger interval", interval, len(ranges))
+	}
+
+	if err := a.mustAuth(); err != nil {
+		return err
+	}
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	if req.Streams, err = graylog.NewResolver(c).StreamIDs(cmd.Context(), req.Streams); err != nil {
+		return err
+	}
+
+	// The first failure (including a dry run) cancels the remaining buckets.
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+	buckets := make([]histogramBucket, len(ranges))
+	sem := make(chan struct{}, histogramConcurrency)
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for i, r := range ranges {
+		i, r := i, r
+		sem <- struct{}{}
+		if ctx.Err() != nil {
+			<-sem
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			n, err := c.CountMessages(ctx, req.Query, req.Streams, graylog.AbsoluteTimerange(r[0], r[1]))
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				cancel()
+				return
+			}
+			buckets[i] = histogramBucket{Bucket: r[0].Format(graylog.TimestampLayout), Count: n}
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if a.runtime.Format == "json" {
+		return output.PrintJSON(cmd.OutOrStdout(), buckets)
+	}
+	return printHistogram(cmd, buckets, interval)
+}
+
+func printHistogram(cmd *cobra.Command, buckets []histogramBucket, interval time.Duration) error {
+	peak, total := 0, 0
+	for _, b := range buckets {
+		total += b.Count
+		if b.Count > peak {
+			peak = b.Count
+		}
+	}
+
+	tw := table.NewWriter()
+	tw.AppendHeader(table.Row{"BUCKET", "COUNT", ""})
+	for _, b := range buckets {
+		width := 0
+		if peak > 0 {
+			width = b.Count * histogramBarWidth / peak
+		}
+		if width == 0 && b.Count > 0 {
+			width = 1
+		}
+		tw.AppendRow(table.Row{b.Bucket, b.Count, strings.Repeat("█", width)})
+	}
+	tw.AppendFooter(table.Row{"TOTAL", total, ""})
+	if _, err := fmt.Fprintln(cmd.OutOrStdout(), tw.Render()); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(cmd.OutOrStdout(), "%s  interval=%s peak=%d\n", sparkline(buckets, peak), interval, peak)
+	return err
+}
+
+func sparkline(buckets []histogramBucket, peak int) string {
+	levels := []rune("▁▂▃▄▅▆▇█")
+	var b strings.Builder
+	for _, bucket := range buckets {
+		idx := 0
+		if peak > 0 {
+			idx = bucket.Count * (len(levels) - 1) / peak
+		}
+		b.WriteRune(levels[idx])
+	}
+	return b.String()
+}
+
+func histogramInterval(span time.Duration) time.Duration {
+	for _, iv := range histogramIntervals {
+		if span/iv <= histogramMaxBuckets {
+			return iv
+		}
+	}
+	return histogramIntervals[len(histogramIntervals)-1]
+}
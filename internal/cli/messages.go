@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dsantic/graylog-cli/internal/graylog"
+	"github.com/dsantic/graylog-cli/internal/output"
+)
+
+func (a *App) newMessagesCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "messages", Short: "Message commands"}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "get [index] <id>",
+		Short: "Show every field of a single message",
+		Long:  "Show every field of a single message. Without an index the message is located by searching for its _id.",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := a.mustAuth(); err != nil {
+				return err
+			}
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			var msg graylog.MessageResponse
+			if len(args) == 2 {
+				msg, err = c.GetMessage(cmd.Context(), strings.TrimSpace(args[0]), strings.TrimSpace(args[1]))
+			} else {
+				msg, err = c.FindMessage(cmd.Context(), strings.TrimSpace(args[0]))
+			}
+			if err != nil {
+				return err
+			}
+			if a.runtime.Format == "json" {
+				return output.PrintJSON(cmd.OutOrStdout(), msg)
+			}
+			return output.PrintKeyValueTable(cmd.OutOrStdout(), msg.Message)
+		},
+	})
+	return cmd
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dsantic/graylog-cli/internal/graylog"
+	"github.com/dsantic/graylog-cli/internal/output"
+	"github.com/dsantic/graylog-cli/internal/patterns"
+)
+
+// pageSink receives search result pages; SearchStream prints them and
+// patternSink clusters them. Abort ends the output after a failed search.
+type pageSink interface {
+	WritePage(resp graylog.SearchMessagesResponse) error
+	Close() error
+	Abort(err error) error
+}
+
+type patternSink struct {
+	a         *App
+	cmd       *cobra.Command
+	field     string
+	top       int
+	clusterer *patterns.Clusterer
+	skipped   int
+}
+
+func (a *App) newPageSink(cmd *cobra.Command, common *searchCommon) pageSink {
+	if common.Cluster {
+		return &patternSink{a: a, cmd: cmd, field: common.ClusterField, top: common.Top, clusterer: patterns.NewClusterer()}
+	}
+	return output.NewSearchStream(cmd.OutOrStdout(), a.runtime.Format, a.runtime.MaxWidth)
+}
+
+func (s *patternSink) WritePage(resp graylog.SearchMessagesResponse) error {
+	for _, row := range graylog.NormalizeSearchResponse(resp).Rows {
+		v, ok := row[s.field]
+		if !ok || v == nil {
+			s.skipped++
+			continue
+		}
+		msg, ok := v.(string)
+		if !ok {
+			msg = fmt.Sprint(v)
+		}
+		ts, _ := graylog.ParseTimestamp(row["timestamp"])
+		s.clusterer.Add(msg, ts)
+	}
+	return nil
+}
+
+// Abort prints nothing: patterns of a partial result would be misleading.
+func (s *patternSink) Abort(error) error { return nil }
+
+func (s *patternSink) Close() error {
+	list := s.clusterer.Patterns()
+	distinct := len(list)
+	if s.top > 0 && len(list) > s.top {
+		list = list[:s.top]
+	}
+	w := s.cmd.OutOrStdout()
+	if s.a.runtime.Format == "json" {
+		return output.PrintJSON(w, map[string]any{
+			"patterns": list,
+			"metadata": map[string]any{
+				"field":             s.field,
+				"total_messages":    s.clusterer.Total(),
+				"distinct_patterns": distinct,
+				"skipped":           s.skipped,
+			},
+		})
+	}
+	if err := output.PrintPatternTable(w, list, s.clusterer.Total(), s.a.runtime.MaxWidth); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%d messages, %d patterns\n", s.clusterer.Total(), distinct)
+	return err
+}
+
+// withClusterFields makes sure the clustered field and timestamp are fetched.
+func withClusterFields(fields []string, field string) []string {
+	out := append([]string{}, fields...)
+	for _, f := range []string{"timestamp", field} {
+		if !containsString(out, f) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func (a *App) newSearchPatternsCmd() *cobra.Command {
+	common := &searchCommon{Cluster: true}
+	cmd := &cobra.Command{
+		Use:   "patterns",
+		Short: "Group matching messages into patterns with variable parts masked",
+	}
+	bindSearchQueryFlags(cmd, common)
+	cmd.PersistentFlags().StringVar(&common.ClusterField, "field", "message", "Field to cluster")
+	cmd.PersistentFlags().IntVar(&common.Limit, "limit", 500, "Page size")
+	cmd.PersistentFlags().IntVar(&common.MaxResults, "max-results", graylog.DefaultResultWindow, "Cluster at most N messages (0 fetches every page)")
+	cmd.PersistentFlags().IntVar(&common.Top, "top", 25, "Show the N most frequent patterns (0 shows all)")
+
+	run := func(cmd *cobra.Command, req graylog.SearchMessagesRequest) error {
+		if strings.TrimSpace(common.ClusterField) == "" {
+			return fmt.Errorf("--field is required")
+		}
+		common.All = true
+		req.Fields = []string{"timestamp", strings.TrimSpace(common.ClusterField)}
+		return a.runSearch(cmd, common, req)
+	}
+	cmd.AddCommand(a.newTimerangeCmds(common, run)...)
+	return cmd
+}
@@ -0,0 +1,245 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"github.com/dsantic/graylog-cli/internal/config"
+	"github.com/dsantic/graylog-cli/internal/output"
+)
+
+func (a *App) newQueryCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "query", Short: "Manage saved query templates"}
+	cmd.AddCommand(a.newQueryListCmd(), a.newQueryShowCmd(), a.newQueryAddCmd(), a.newQueryRmCmd())
+	return cmd
+}
+
+func (a *App) savedQuery(name string) (config.SavedQuery, error) {
+	q, ok := a.cfg.Queries[name]
+	if !ok {
+		return config.SavedQuery{}, fmt.Errorf("saved query %q not found (see graylogctl query list)", name)
+	}
+	return q, nil
+}
+
+func (a *App) newQueryListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List saved queries",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if a.runtime.Format == "json" {
+				queries := a.cfg.Queries
+				if queries == nil {
+					queries = map[string]config.SavedQuery{}
+				}
+				return output.PrintJSON(cmd.OutOrStdout(), queries)
+			}
+
+			names := make([]string, 0, len(a.cfg.Queries))
+			for name := range a.cfg.Queries {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			tw := table.NewWriter()
+			tw.AppendHeader(table.Row{"NAME", "QUERY", "VARS", "DESCRIPTION"})
+			for _, name := range names {
+				q := a.cfg.Queries[name]
+				tw.AppendRow(table.Row{
+					name,
+					output.FormatCell(q.Query, a.runtime.MaxWidth),
+					strings.Join(q.Placeholders(), ","),
+					output.FormatCell(q.Description, a.runtime.MaxWidth),
+				})
+			}
+			_, err := fmt.Fprintln(cmd.OutOrStdout(), tw.Render())
+			return err
+		},
+	}
+}
+
+func (a *App) newQueryShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show a saved query",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			q, err := a.savedQuery(args[0])
+			if err != nil {
+				return err
+			}
+			if a.runtime.Format == "json" {
+				return output.PrintJSON(cmd.OutOrStdout(), q)
+			}
+			defaults := make([]string, 0, len(q.Vars))
+			for k, v := range q.Vars {
+				defaults = append(defaults, k+"="+v)
+			}
+			sort.Strings(defaults)
+			return output.PrintKeyValueTable(cmd.OutOrStdout(), map[string]any{
+				"name":        args[0],
+				"description": q.Description,
+				"query":       q.Query,
+				"fields":      strings.Join(q.Fields, ","),
+				"streams":     strings.Join(q.Streams, ","),
+				"since":       q.Since,
+				"vars":        strings.Join(q.Placeholders(), ","),
+				"defaults":    strings.Join(defaults, ","),
+			})
+		},
+	}
+}
+
+func (a *App) newQueryAddCmd() *cobra.Command {
+	var (
+		q     config.SavedQuery
+		vars  []string
+		force bool
+	)
+	cmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Save a query template",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := strings.TrimSpace(args[0])
+			if name == "" {
+				return fmt.Errorf("query name must not be empty")
+			}
+			if _, exists := a.cfg.Queries[name]; exists && !force {
+				return fmt.Errorf("saved query %q already exists (use --force to replace it)", name)
+			}
+			q.Query = strings.TrimSpace(q.Query)
+			if q.Since != "" {
+				if _, err := relativeTimerange(0, q.Since); err != nil {
+					return err
+				}
+			}
+			defaults, err := config.ParseVars(vars)
+			if err != nil {
+				return err
+			}
+			if len(defaults) > 0 {
+				q.Vars = defaults
+			}
+			if a.cfg.Queries == nil {
+				a.cfg.Queries = map[string]config.SavedQuery{}
+			}
+			a.cfg.Queries[name] = q
+			if err := config.SaveConfig(a.cfg); err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "saved query %q\n", name)
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&q.Query, "query", "", "Graylog query; may use ${var} placeholders")
+	cmd.Flags().StringVar(&q.Description, "description", "", "Short description")
+	cmd.Flags().StringSliceVar(&q.Fields, "fields", nil, "Default comma-separated fields")
+	cmd.Flags().StringSliceVar(&q.Streams, "stream", nil, "Default stream id or title (repeatable)")
+	cmd.Flags().StringVar(&q.Since, "since", "", "Default relative timerange (e.g. 15m, 1d)")
+	cmd.Flags().StringArrayVar(&vars, "var", nil, "Default variable value name=value (repeatable)")
+	cmd.Flags().BoolVar(&force, "force", false, "Replace an existing query with the same name")
+	_ = cmd.MarkFlagRequired("query")
+	return cmd
+}
+
+func (a *App) newQueryRmCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "rm <name>",
+		Aliases: []string{"remove"},
+		Short:   "Delete a saved query",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := a.savedQuery(args[0]); err != nil {
+				return err
+			}
+			delete(a.cfg.Queries, args[0])
+			if err := config.SaveConfig(a.cfg); err != nil {
+				return err
+			}
+			_, err := fmt.Fprintf(cmd.OutOrStdout(), "removed query %q\n", args[0])
+			return err
+		},
+	}
+}
+
+func (a *App) newSearchRunCmd() *cobra.Command {
+	var (
+		vars       []string
+		since      string
+		from, to   string
+		fields     string
+		streams    []string
+		limit      int
+		all        bool
+		maxResults int
+		cluster    bool
+	)
+	common := &searchCommon{ClusterField: "message"}
+	cmd := &cobra.Command{
+		Use:   "run <name>",
+		Short: "Run a saved query template",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if since != "" && (from != "" || to != "") {
+				return fmt.Errorf("use either --since or --from/--to")
+			}
+			q, err := a.savedQuery(args[0])
+			if err != nil {
+				return err
+			}
+			values, err := config.ParseVars(vars)
+			if err != nil {
+				return err
+			}
+			q, err = q.Expand(values)
+			if err != nil {
+				return fmt.Errorf("query %q: %w", args[0], err)
+			}
+
+			common.Query = q.Query
+			common.Fields = strings.Join(q.Fields, ",")
+			if cmd.Flags().Changed("fields") {
+				common.Fields = fields
+			}
+			common.Streams = q.Streams
+			if cmd.Flags().Changed("stream") {
+				common.Streams = streams
+			}
+			common.Limit = limit
+			common.All = all
+			common.MaxResults = maxResults
+			common.Cluster = cluster
+			req := buildSearchRequest(common)
+
+			if from != "" || to != "" {
+				req.Timerange, err = absoluteTimerange(from, to, "", "", time.Now())
+			} else {
+				if since == "" {
+					since = q.Since
+				}
+				req.Timerange, err = relativeTimerange(300, since)
+			}
+			if err != nil {
+				return err
+			}
+			return a.runSearch(cmd, common, req)
+		},
+	}
+	cmd.Flags().StringArrayVar(&vars, "var", nil, "Template variable name=value (repeatable)")
+	cmd.Flags().StringVar(&since, "since", "", "Relative timerange, overriding the saved one (default 5m)")
+	cmd.Flags().StringVar(&from, "from", "", "Absolute start time instead of a relative range")
+	cmd.Flags().StringVar(&to, "to", "", "Absolute end time (default now)")
+	cmd.Flags().StringVar(&fields, "fields", "", "Comma-separated fields, overriding the saved ones")
+	cmd.Flags().StringSliceVar(&streams, "stream", nil, "Stream id or title, overriding the saved ones (repeatable)")
+	cmd.Flags().IntVar(&limit, "limit", 50, "Result size")
+	cmd.Flags().BoolVar(&all, "all", false, "Page through all results, using --limit as page size")
+	cmd.Flags().IntVar(&maxResults, "max-results", 0, "Page through results until N messages were fetched (implies --all)")
+	cmd.Flags().BoolVar(&cluster, "cluster", false, "Group results into message patterns instead of listing them")
+	return cmd
+}
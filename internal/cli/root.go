@@ -2,9 +2,13 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -17,6 +21,8 @@ type App struct {
 	v       *viper.Viper
 	cfg     *config.Config
 	runtime config.Runtime
+	stdout  io.Writer
+	stderr  io.Writer
 }
 
 func NewRootCmd() *cobra.Command {
@@ -38,6 +44,8 @@ func NewRootCmd() *cobra.Command {
 			}
 			app.cfg = cfg
 			app.runtime = r
+			app.stdout = cmd.OutOrStdout()
+			app.stderr = cmd.ErrOrStderr()
 			return nil
 		},
 	}
@@ -51,6 +59,13 @@ func NewRootCmd() *cobra.Command {
 	cmd.PersistentFlags().String("format", config.DefaultFormat, "Output format: table|json")
 	cmd.PersistentFlags().String("profile", config.DefaultProfile, "Config profile name")
 	cmd.PersistentFlags().Int("max-width", 0, "Maximum table cell width (0 disables truncation)")
+	cmd.PersistentFlags().Bool("no-cache", false, "Bypass the local metadata cache")
+	cmd.PersistentFlags().Bool("dry-run", false, "Print the API request instead of sending it (auth redacted)")
+	cmd.PersistentFlags().Bool("as-curl", false, "Like --dry-run, but print the request as a curl command")
+	cmd.PersistentFlags().Int("retries", config.DefaultRetries, "Retries for transient failures (transport errors, 429, 502-504) of idempotent requests")
+	cmd.PersistentFlags().Float64("rate-limit", 0, "Maximum API requests per second (0 disables)")
+	cmd.PersistentFlags().BoolP("verbose", "v", false, "Log each API request to stderr with status, size and timings")
+	cmd.PersistentFlags().Bool("trace", false, "Like --verbose, plus request/response headers and request bodies (secrets redacted)")
 
 	app.bindEnv("url", config.EnvURL)
 	app.bindEnv("api-base", config.EnvAPIBase)
@@ -60,6 +75,9 @@ func NewRootCmd() *cobra.Command {
 	app.bindEnv("timeout", config.EnvTimeout)
 	app.bindEnv("format", config.EnvFormat)
 	app.bindEnv("profile", config.EnvProfile)
+	app.bindEnv("no-cache", config.EnvNoCache)
+	app.bindEnv("retries", config.EnvRetries)
+	app.bindEnv("rate-limit", config.EnvRateLimit)
 
 	cmd.AddCommand(
 		app.newAuthCmd(),
@@ -68,6 +86,10 @@ func NewRootCmd() *cobra.Command {
 		app.newNodesCmd(),
 		app.newIndicesCmd(),
 		app.newSearchCmd(),
+		app.newMessagesCmd(),
+		app.newCacheCmd(),
+		app.newQueryCmd(),
+		app.newAPICmd(),
 	)
 
 	return cmd
@@ -80,16 +102,49 @@ func (a *App) bindEnv(key, env string) {
 }
 
 func (a *App) client() (*graylog.Client, error) {
+	rc, err := a.responseCache(a.runtime.Profile, cacheBase(a.runtime.URL, a.runtime.APIBase), a.runtime.CacheTTL)
+	if err != nil {
+		return nil, err
+	}
 	return graylog.NewClient(graylog.ClientConfig{
-		BaseURL:  a.runtime.URL,
-		APIBase:  a.runtime.APIBase,
-		Token:    a.runtime.Token,
-		Session:  a.runtime.Session,
-		Insecure: a.runtime.Insecure,
-		Timeout:  a.runtime.Timeout,
+		BaseURL:   a.runtime.URL,
+		APIBase:   a.runtime.APIBase,
+		Token:     a.runtime.Token,
+		Session:   a.runtime.Session,
+		Insecure:  a.runtime.Insecure,
+		Timeout:   a.runtime.Timeout,
+		Cache:     rc,
+		DryRun:    a.dryRunMode(),
+		DryRunOut: a.stdout,
+		Retry:     graylog.RetryPolicy{MaxRetries: a.runtime.Retries},
+		RateLimit: a.runtime.RateLimit,
+		Trace:     a.traceLevel(),
+		TraceOut:  a.stderr,
 	})
 }
 
+func (a *App) dryRunMode() graylog.DryRunMode {
+	switch {
+	case a.runtime.AsCurl:
+		return graylog.DryRunCurl
+	case a.runtime.DryRun:
+		return graylog.DryRunRequest
+	default:
+		return graylog.DryRunOff
+	}
+}
+
+func (a *App) traceLevel() graylog.TraceLevel {
+	switch {
+	case a.runtime.Trace:
+		return graylog.TraceFull
+	case a.runtime.Verbose:
+		return graylog.TraceVerbose
+	default:
+		return graylog.TraceOff
+	}
+}
+
 func (a *App) mustAuth() error {
 	if a.runtime.Token == "" && a.runtime.Session == "" {
 		return fmt.Errorf("no auth configured; set --token or --session, or run graylogctl auth login")
@@ -97,9 +152,42 @@ func (a *App) mustAuth() error {
 	return nil
 }
 
+// ExitError makes the process exit with Code. Err, if set, is printed to
+// stderr first.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
 func Execute() {
-	if err := NewRootCmd().ExecuteContext(context.Background()); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	cmd, err := NewRootCmd().ExecuteContextC(ctx)
+	stop()
+	if isCheckCmd(cmd) {
+		err = checkUnknown(err)
+	}
+	if err == nil || errors.Is(err, graylog.ErrDryRun) {
+		return
+	}
+	code := 1
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		code = exitErr.Code
+		if exitErr.Err == nil {
+			os.Exit(code)
+		}
 	}
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(code)
 }
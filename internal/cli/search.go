@@ -1,8 +1,10 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -11,80 +13,139 @@ import (
 )
 
 type searchCommon struct {
-	Query     string
-	Fields    string
-	Offset    int
-	Limit     int
-	Streams   []string
-	Sort      string
-	SortOrder string
+	Query        string
+	Fields       string
+	Offset       int
+	Limit        int
+	Streams      []string
+	Sort         string
+	SortOrder    string
+	All          bool
+	MaxResults   int
+	Split        bool
+	Window       int
+	Concurrency  int
+	Validate     bool
+	Profiles     []string
+	AllProfiles  bool
+	ResolveNames bool
+	Cluster      bool
+	ClusterField string
+	Top          int
 }
 
+type searchRunner func(cmd *cobra.Command, req graylog.SearchMessagesRequest) error
+
 func (a *App) newSearchCmd() *cobra.Command {
 	searchCmd := &cobra.Command{Use: "search", Short: "Search commands"}
 	messagesCmd := &cobra.Command{Use: "messages", Short: "Search messages"}
 
-	common := &searchCommon{}
+	common := &searchCommon{ClusterField: "message"}
 	bindSearchCommonFlags(messagesCmd, common)
+	messagesCmd.PersistentFlags().BoolVar(&common.Cluster, "cluster", false, "Group results into message patterns instead of listing them")
+	messagesCmd.PersistentFlags().IntVar(&common.Top, "top", 0, "With --cluster, show only the N most frequent patterns")
 
-	messagesCmd.AddCommand(
-		a.newSearchRelativeCmd(common),
-		a.newSearchAbsoluteCmd(common),
-		a.newSearchKeywordCmd(common),
-	)
-	searchCmd.AddCommand(messagesCmd)
+	run := func(cmd *cobra.Command, req graylog.SearchMessagesRequest) error {
+		return a.runSearch(cmd, common, req)
+	}
+	absoluteCmd := a.newSearchAbsoluteCmd(common, run)
+	// --from is the start time here, so the result offset gets its own name.
+	absoluteCmd.Flags().IntVar(&common.Offset, "offset", 0, "Result offset")
+	absoluteCmd.Flags().BoolVar(&common.Split, "split", false, "Split the range into slices under the result window so --offset/--limit can go past it (with --all, fetch every message)")
+	absoluteCmd.Flags().IntVar(&common.Window, "window-size", graylog.DefaultResultWindow, "Result window (max from+size) per slice with --split")
+	absoluteCmd.Flags().IntVar(&common.Concurrency, "concurrency", 1, "Slices fetched in parallel with --split")
+	messagesCmd.AddCommand(a.newSearchRelativeCmd(common, run), absoluteCmd, a.newSearchKeywordCmd(common, run))
+	searchCmd.AddCommand(messagesCmd, a.newSearchTailCmd(), a.newSearchAggregateCmd(), a.newSearchHistogramCmd(), a.newSearchExportCmd(), a.newSearchValidateCmd(), a.newSearchFieldsCmd(), a.newSearchContextCmd(), a.newSearchPatternsCmd(), a.newSearchRunCmd(), a.newSearchCheckCmd(), a.newSearchCompareCmd())
 	return searchCmd
 }
 
-func bindSearchCommonFlags(cmd *cobra.Command, common *searchCommon) {
+func (a *App) newTimerangeCmds(common *searchCommon, run searchRunner) []*cobra.Command {
+	return []*cobra.Command{
+		a.newSearchRelativeCmd(common, run),
+		a.newSearchAbsoluteCmd(common, run),
+		a.newSearchKeywordCmd(common, run),
+	}
+}
+
+// newBoundedTimerangeCmds omits keyword, for commands that need concrete
+// start and end times on the client.
+func (a *App) newBoundedTimerangeCmds(common *searchCommon, run searchRunner) []*cobra.Command {
+	return []*cobra.Command{
+		a.newSearchRelativeCmd(common, run),
+		a.newSearchAbsoluteCmd(common, run),
+	}
+}
+
+func bindSearchQueryFlags(cmd *cobra.Command, common *searchCommon) {
 	cmd.PersistentFlags().StringVar(&common.Query, "query", "", "Graylog query")
+	cmd.PersistentFlags().StringSliceVar(&common.Streams, "stream", nil, "Restrict search to stream id or title (repeatable)")
+	_ = cmd.MarkPersistentFlagRequired("query")
+}
+
+func bindSearchCommonFlags(cmd *cobra.Command, common *searchCommon) {
+	bindSearchQueryFlags(cmd, common)
 	cmd.PersistentFlags().StringVar(&common.Fields, "fields", "timestamp,source,message", "Comma-separated fields")
 	cmd.PersistentFlags().IntVar(&common.Offset, "from", 0, "Result offset")
 	cmd.PersistentFlags().IntVar(&common.Limit, "limit", 50, "Result size")
-	cmd.PersistentFlags().StringSliceVar(&common.Streams, "stream", nil, "Restrict search to stream id (repeatable)")
 	cmd.PersistentFlags().StringVar(&common.Sort, "sort", "", "Sort field")
 	cmd.PersistentFlags().StringVar(&common.SortOrder, "sort-order", "desc", "Sort order asc|desc")
-	_ = cmd.MarkPersistentFlagRequired("query")
+	cmd.PersistentFlags().BoolVar(&common.All, "all", false, "Page through all results, using --limit as page size")
+	cmd.PersistentFlags().IntVar(&common.MaxResults, "max-results", 0, "Page through results until N messages were fetched (implies --all)")
+	cmd.PersistentFlags().BoolVar(&common.Validate, "validate", false, "Validate the query first and refuse to run invalid queries")
+	cmd.PersistentFlags().StringSliceVar(&common.Profiles, "profiles", nil, "Run the search against these config profiles and merge the results")
+	cmd.PersistentFlags().BoolVar(&common.AllProfiles, "all-profiles", false, "Run the search against every config profile with a url")
+	cmd.PersistentFlags().BoolVar(&common.ResolveNames, "resolve-names", false, "Show input, node and stream names instead of ids in results")
 }
 
-func (a *App) newSearchRelativeCmd(common *searchCommon) *cobra.Command {
-	var seconds int
+func (a *App) newSearchRelativeCmd(common *searchCommon, run searchRunner) *cobra.Command {
+	var (
+		seconds int
+		since   string
+	)
 	cmd := &cobra.Command{
 		Use:   "relative",
 		Short: "Relative time-range message search",
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			if seconds <= 0 {
-				return fmt.Errorf("--seconds must be > 0")
+			if cmd.Flags().Changed("seconds") && cmd.Flags().Changed("since") {
+				return fmt.Errorf("use either --seconds or --since")
+			}
+			tr, err := relativeTimerange(seconds, since)
+			if err != nil {
+				return err
 			}
 			req := buildSearchRequest(common)
-			req.Timerange = graylog.SearchTimerange{Type: "relative", Range: seconds}
-			return a.runSearch(cmd, req)
+			req.Timerange = tr
+			return run(cmd, req)
 		},
 	}
 	cmd.Flags().IntVar(&seconds, "seconds", 300, "Relative timerange in seconds")
+	cmd.Flags().StringVar(&since, "since", "", "Relative timerange as a duration (e.g. 15m, 2h30m, 1d)")
 	return cmd
 }
 
-func (a *App) newSearchAbsoluteCmd(common *searchCommon) *cobra.Command {
-	var from, to string
+func (a *App) newSearchAbsoluteCmd(common *searchCommon, run searchRunner) *cobra.Command {
+	var from, to, around, window string
 	cmd := &cobra.Command{
 		Use:   "absolute",
 		Short: "Absolute time-range message search",
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			if strings.TrimSpace(from) == "" || strings.TrimSpace(to) == "" {
-				return fmt.Errorf("--from and --to are required")
+			tr, err := absoluteTimerange(from, to, around, window, time.Now())
+			if err != nil {
+				return err
 			}
 			req := buildSearchRequest(common)
-			req.Timerange = graylog.SearchTimerange{Type: "absolute", From: strings.TrimSpace(from), To: strings.TrimSpace(to)}
-			return a.runSearch(cmd, req)
+			req.Timerange = tr
+			return run(cmd, req)
 		},
 	}
-	cmd.Flags().StringVar(&from, "from", "", "Start timestamp (ISO8601)")
-	cmd.Flags().StringVar(&to, "to", "", "End timestamp (ISO8601)")
+	cmd.Flags().StringVar(&from, "from", "", "Start time (RFC3339, epoch, 'yesterday 10:00', '2h ago', ...)")
+	cmd.Flags().StringVar(&to, "to", "", "End time, same forms as --from (default now)")
+	cmd.Flags().StringVar(&around, "around", "", "Center time; searches --window before and after it")
+	cmd.Flags().StringVar(&window, "window", "5m", "Half-width of the --around range (e.g. 5m, 1h)")
 	return cmd
 }
 
-func (a *App) newSearchKeywordCmd(common *searchCommon) *cobra.Command {
+func (a *App) newSearchKeywordCmd(common *searchCommon, run searchRunner) *cobra.Command {
 	var keyword string
 	cmd := &cobra.Command{
 		Use:   "keyword",
@@ -95,14 +156,23 @@ func (a *App) newSearchKeywordCmd(common *searchCommon) *cobra.Command {
 			}
 			req := buildSearchRequest(common)
 			req.Timerange = graylog.SearchTimerange{Type: "keyword", Keyword: strings.TrimSpace(keyword)}
-			return a.runSearch(cmd, req)
+			return run(cmd, req)
 		},
 	}
 	cmd.Flags().StringVar(&keyword, "keyword", "", "Keyword timerange (e.g. 'last five minutes')")
 	return cmd
 }
 
-func (a *App) runSearch(cmd *cobra.Command, req graylog.SearchMessagesRequest) error {
+func (a *App) runSearch(cmd *cobra.Command, common *searchCommon, req graylog.SearchMessagesRequest) error {
+	if common.MaxResults < 0 {
+		return fmt.Errorf("--max-results must be >= 0")
+	}
+	if common.Cluster {
+		req.Fields = withClusterFields(req.Fields, common.ClusterField)
+	}
+	if len(common.Profiles) > 0 || common.AllProfiles {
+		return a.runFanoutSearch(cmd, common, req)
+	}
 	if err := a.mustAuth(); err != nil {
 		return err
 	}
@@ -110,10 +180,42 @@ func (a *App) runSearch(cmd *cobra.Command, req graylog.SearchMessagesRequest) e
 	if err != nil {
 		return err
 	}
+	resolver := graylog.NewResolver(c)
+	resolver.Warn = cmd.ErrOrStderr()
+	if req.Streams, err = resolver.StreamIDs(cmd.Context(), req.Streams); err != nil {
+		return err
+	}
+	if common.Validate {
+		if err := a.validateBeforeSearch(cmd, c, req); err != nil {
+			return err
+		}
+	}
+	if cmd.Flags().Changed("fields") {
+		a.warnUnknownFields(cmd, c, req.Fields)
+	}
+	if common.Split {
+		return a.runSearchSliced(cmd, c, common, req)
+	}
+	if common.All || common.MaxResults > 0 {
+		return a.runSearchAll(cmd, c, common, req)
+	}
+
 	resp, err := c.SearchMessages(cmd.Context(), req)
 	if err != nil {
 		return err
 	}
+	if common.ResolveNames {
+		if err := resolver.EnrichSearchResponse(cmd.Context(), &resp); err != nil {
+			return err
+		}
+	}
+	if common.Cluster {
+		sink := a.newPageSink(cmd, common)
+		if err := sink.WritePage(resp); err != nil {
+			return err
+		}
+		return sink.Close()
+	}
 
 	normalized := graylog.NormalizeSearchResponse(resp)
 	if a.runtime.Format == "json" {
@@ -122,6 +224,65 @@ func (a *App) runSearch(cmd *cobra.Command, req graylog.SearchMessagesRequest) e
 	return output.PrintSearchTable(cmd.OutOrStdout(), resp, a.runtime.MaxWidth)
 }
 
+func (a *App) runSearchAll(cmd *cobra.Command, c *graylog.Client, common *searchCommon, req graylog.SearchMessagesRequest) error {
+	if req.Size <= 0 {
+		return fmt.Errorf("--limit must be > 0 when paging")
+	}
+	stream := a.newPageSink(cmd, common)
+	resolver := graylog.NewResolver(c)
+	resolver.Warn = cmd.ErrOrStderr()
+	write := func(page graylog.SearchMessagesResponse) error {
+		if common.ResolveNames {
+			if err := resolver.EnrichSearchResponse(cmd.Context(), &page); err != nil {
+				return err
+			}
+		}
+		return stream.WritePage(page)
+	}
+	if _, err := c.SearchMessagesPages(cmd.Context(), req, common.MaxResults, write); err != nil {
+		if errors.Is(err, graylog.ErrResultWindow) {
+			err = fmt.Errorf("%w; use `search messages absolute --split` to fetch past it", err)
+		}
+		_ = stream.Abort(err)
+		return err
+	}
+	return stream.Close()
+}
+
+func (a *App) runSearchSliced(cmd *cobra.Command, c *graylog.Client, common *searchCommon, req graylog.SearchMessagesRequest) error {
+	if common.Window <= 0 || common.Concurrency <= 0 {
+		return fmt.Errorf("--window-size and --concurrency must be > 0")
+	}
+	opts := graylog.SliceOptions{
+		Window:      common.Window,
+		Concurrency: common.Concurrency,
+		Offset:      common.Offset,
+		Limit:       common.Limit,
+	}
+	if common.All || common.MaxResults > 0 {
+		opts.Limit = common.MaxResults
+	}
+	if opts.Offset < 0 || opts.Limit < 0 {
+		return fmt.Errorf("--offset and --limit must be >= 0")
+	}
+	resp, err := c.SearchMessagesSliced(cmd.Context(), req, opts)
+	if err != nil {
+		return err
+	}
+	if common.ResolveNames {
+		resolver := graylog.NewResolver(c)
+		resolver.Warn = cmd.ErrOrStderr()
+		if err := resolver.EnrichSearchResponse(cmd.Context(), &resp); err != nil {
+			return err
+		}
+	}
+	stream := a.newPageSink(cmd, common)
+	if err := stream.WritePage(resp); err != nil {
+		return err
+	}
+	return stream.Close()
+}
+
 func buildSearchRequest(common *searchCommon) graylog.SearchMessagesRequest {
 	req := graylog.SearchMessagesRequest{
 		Query:     strings.TrimSpace(common.Query),
@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dsantic/graylog-cli/internal/graylog"
+	"github.com/dsantic/graylog-cli/internal/output"
+)
+
+var messageIDFields = []string{"_id", "gl2_message_id"}
+
+func (a *App) newSearchTailCmd() *cobra.Command {
+	var (
+		query    string
+		fields   string
+		streams  []string
+		interval time.Duration
+		since    time.Duration
+		overlap  time.Duration
+		limit    int
+	)
+	cmd := &cobra.Command{
+		Use:   "tail",
+		Short: "Follow new messages for a query until interrupted",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if interval <= 0 {
+				return fmt.Errorf("--interval must be > 0")
+			}
+			if since < 0 || overlap < 0 {
+				return fmt.Errorf("--since and --overlap must be >= 0")
+			}
+			if limit <= 0 {
+				return fmt.Errorf("--limit must be > 0")
+			}
+			if err := a.mustAuth(); err != nil {
+				return err
+			}
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			streamIDs, err := graylog.NewResolver(c).StreamIDs(cmd.Context(), streams)
+			if err != nil {
+				return err
+			}
+			shown := parseFields(fields)
+			if len(shown) == 0 {
+				shown = []string{"timestamp", "source", "message"}
+			}
+			req := graylog.SearchMessagesRequest{
+				Query:     strings.TrimSpace(query),
+				Fields:    withMessageIDFields(shown),
+				Size:      limit,
+				Streams:   streamIDs,
+				Sort:      "timestamp",
+				SortOrder: "asc",
+			}
+			return a.runTail(cmd, c, req, shown, since, interval, overlap)
+		},
+	}
+	cmd.Flags().StringVar(&query, "query", "*", "Graylog query")
+	cmd.Flags().StringVar(&fields, "fields", "timestamp,source,message", "Comma-separated fields")
+	cmd.Flags().StringSliceVar(&streams, "stream", nil, "Restrict search to stream id or title (repeatable)")
+	cmd.Flags().DurationVar(&interval, "interval", 2*time.Second, "Poll interval")
+	cmd.Flags().Var(newDurationFlag(&since, time.Minute), "since", "Show messages this far back before following (e.g. 30s, 15m, 1d)")
+	cmd.Flags().DurationVar(&overlap, "overlap", 10*time.Second, "Re-query this much of the previous window to catch late messages")
+	cmd.Flags().IntVar(&limit, "limit", 500, "Page size per poll")
+	return cmd
+}
+
+func (a *App) runTail(cmd *cobra.Command, c *graylog.Client, req graylog.SearchMessagesRequest, shown []string, since, interval, overlap time.Duration) error {
+	ctx := cmd.Context()
+	w := cmd.OutOrStdout()
+	seen := map[string]time.Time{}
+	from := time.Now().UTC().Add(-since)
+	polls := 0
+
+	if a.runtime.Format != "json" {
+		header := make([]any, 0, len(shown))
+		for _, f := range shown {
+			header = append(header, strings.ToUpper(f))
+		}
+		if err := output.PrintRowLine(w, header, a.runtime.MaxWidth); err != nil {
+			return err
+		}
+	}
+
+	for {
+		to := time.Now().UTC()
+		req.Timerange = graylog.AbsoluteTimerange(from, to)
+
+		var fresh []map[string]any
+		_, err := c.SearchMessagesPages(ctx, req, 0, func(resp graylog.SearchMessagesResponse) error {
+			for _, row := range graylog.NormalizeSearchResponse(resp).Rows {
+				id := messageID(row)
+				if id == "" {
+					fresh = append(fresh, row)
+					continue
+				}
+				if _, ok := seen[id]; ok {
+					continue
+				}
+				ts, _ := graylog.ParseTimestamp(row["timestamp"])
+				seen[id] = ts
+				fresh = append(fresh, row)
+			}
+			return nil
+		})
+		if ctx.Err() != nil {
+			return nil
+		}
+		polls++
+		if err != nil {
+			if polls == 1 {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "tail: %v\n", err)
+		}
+
+		sort.SliceStable(fresh, func(i, j int) bool {
+			ti, _ := graylog.ParseTimestamp(fresh[i]["timestamp"])
+			tj, _ := graylog.ParseTimestamp(fresh[j]["timestamp"])
+			return ti.Before(tj)
+		})
+		for _, row := range fresh {
+			if err := a.printTailRow(cmd, row, shown); err != nil {
+				return err
+			}
+		}
+
+		if err == nil {
+			from = to.Add(-overlap)
+		}
+		for id, ts := range seen {
+			if !ts.IsZero() && ts.Before(from) {
+				delete(seen, id)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (a *App) printTailRow(cmd *cobra.Command, row map[string]any, shown []string) error {
+	if a.runtime.Format == "json" {
+		obj := make(map[string]any, len(shown))
+		for _, f := range shown {
+			obj[f] = row[f]
+		}
+		return output.PrintJSONLine(cmd.OutOrStdout(), obj)
+	}
+	values := make([]any, 0, len(shown))
+	for _, f := range shown {
+		values = append(values, row[f])
+	}
+	return output.PrintRowLine(cmd.OutOrStdout(), values, a.runtime.MaxWidth)
+}
+
+func withMessageIDFields(shown []string) []string {
+	fields := append([]string{}, shown...)
+	for _, f := range append([]string{"timestamp"}, messageIDFields...) {
+		if !containsString(fields, f) {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+func messageID(row map[string]any) string {
+	for _, f := range messageIDFields {
+		if v, ok := row[f].(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dsantic/graylog-cli/internal/graylog"
+	"github.com/dsantic/graylog-cli/internal/timeexpr"
+)
+
+func relativeTimerange(seconds int, since string) (graylog.SearchTimerange, error) {
+	if strings.TrimSpace(since) != "" {
+		d, err := timeexpr.ParseDuration(since)
+		if err != nil {
+			return graylog.SearchTimerange{}, fmt.Errorf("--since: %w", err)
+		}
+		if d < time.Second {
+			return graylog.SearchTimerange{}, fmt.Errorf("--since must be at least 1s, got %s", d)
+		}
+		seconds = int((d + time.Second - 1) / time.Second)
+	}
+	if seconds <= 0 {
+		return graylog.SearchTimerange{}, fmt.Errorf("--seconds must be > 0")
+	}
+	return graylog.SearchTimerange{Type: "relative", Range: seconds}, nil
+}
+
+func absoluteTimerange(from, to, around, window string, now time.Time) (graylog.SearchTimerange, error) {
+	if strings.TrimSpace(around) != "" {
+		if strings.TrimSpace(from) != "" || strings.TrimSpace(to) != "" {
+			return graylog.SearchTimerange{}, fmt.Errorf("--around cannot be combined with --from/--to")
+		}
+		center, err := timeexpr.ParseTime(around, now)
+		if err != nil {
+			return graylog.SearchTimerange{}, fmt.Errorf("--around: %w", err)
+		}
+		w, err := timeexpr.ParseDuration(window)
+		if err != nil {
+			return graylog.SearchTimerange{}, fmt.Errorf("--window: %w", err)
+		}
+		if w <= 0 {
+			return graylog.SearchTimerange{}, fmt.Errorf("--window must be > 0")
+		}
+		return graylog.AbsoluteTimerange(center.Add(-w), center.Add(w)), nil
+	}
+
+	if strings.TrimSpace(from) == "" {
+		return graylog.SearchTimerange{}, fmt.Errorf("--from is required (or use --around)")
+	}
+	if strings.TrimSpace(to) == "" {
+		to = "now"
+	}
+	start, err := timeexpr.ParseTime(from, now)
+	if err != nil {
+		return graylog.SearchTimerange{}, fmt.Errorf("--from: %w", err)
+	}
+	end, err := timeexpr.ParseTime(to, now)
+	if err != nil {
+		return graylog.SearchTimerange{}, fmt.Errorf("--to: %w", err)
+	}
+	if !end.After(start) {
+		return graylog.SearchTimerange{}, fmt.Errorf("--to (%s) must be after --from (%s)", end.Format(time.RFC3339), start.Format(time.RFC3339))
+	}
+	return graylog.AbsoluteTimerange(start, end), nil
+}
+
+// durationFlag is a pflag.Value backed by timeexpr.ParseDuration so duration
+// flags also accept days and weeks (1d, 2w).
+type durationFlag struct {
+	d *time.Duration
+}
+
+func newDurationFlag(d *time.Duration, value time.Duration) durationFlag {
+	*d = value
+	return durationFlag{d: d}
+}
+
+func (f durationFlag) String() string {
+	if f.d == nil {
+		return "0s"
+	}
+	return f.d.String()
+}
+
+func (f durationFlag) Set(s string) error {
+	d, err := timeexpr.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*f.d = d
+	return nil
+}
+
+func (f durationFlag) Type() string {
+	return "duration"
+}
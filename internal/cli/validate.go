@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"github.com/dsantic/graylog-cli/internal/graylog"
+	"github.com/dsantic/graylog-cli/internal/output"
+)
+
+func (a *App) newSearchValidateCmd() *cobra.Command {
+	var (
+		query   string
+		streams []string
+	)
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate query syntax with Graylog's query validator",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := a.mustAuth(); err != nil {
+				return err
+			}
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			streamIDs, err := graylog.NewResolver(c).StreamIDs(cmd.Context(), streams)
+			if err != nil {
+				return err
+			}
+			resp, err := c.ValidateQuery(cmd.Context(), graylog.QueryValidationRequest{Query: strings.TrimSpace(query), Streams: streamIDs})
+			if err != nil {
+				return err
+			}
+
+			if a.runtime.Format == "json" {
+				err = output.PrintJSON(cmd.OutOrStdout(), resp)
+			} else {
+				err = printValidation(cmd.OutOrStdout(), resp)
+			}
+			if err != nil {
+				return err
+			}
+			if resp.Invalid() {
+				return fmt.Errorf("query is invalid")
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&query, "query", "", "Graylog query")
+	cmd.Flags().StringSliceVar(&streams, "stream", nil, "Restrict validation to stream id or title (repeatable)")
+	_ = cmd.MarkFlagRequired("query")
+	return cmd
+}
+
+// validateBeforeSearch runs the query validator and refuses queries it
+// reports as errors; warnings are shown but do not block the search.
+func (a *App) validateBeforeSearch(cmd *cobra.Command, c *graylog.Client, req graylog.SearchMessagesRequest) error {
+	tr := req.Timerange
+	resp, err := c.ValidateQuery(cmd.Context(), graylog.QueryValidationRequest{Query: req.Query, Timerange: &tr, Streams: req.Streams})
+	if err != nil {
+		return fmt.Errorf("validate query: %w", err)
+	}
+	if len(resp.Explanations) > 0 {
+		if err := printValidation(cmd.ErrOrStderr(), resp); err != nil {
+			return err
+		}
+	}
+	if resp.Invalid() {
+		return fmt.Errorf("query is invalid; not running search")
+	}
+	return nil
+}
+
+func printValidation(w io.Writer, resp graylog.QueryValidationResponse) error {
+	if _, err := fmt.Fprintf(w, "status: %s\n", resp.Status); err != nil {
+		return err
+	}
+	if len(resp.Explanations) == 0 {
+		return nil
+	}
+	tw := table.NewWriter()
+	tw.AppendHeader(table.Row{"POSITION", "TYPE", "TITLE", "EXPLANATION"})
+	for _, e := range resp.Explanations {
+		tw.AppendRow(table.Row{
+			fmt.Sprintf("%d:%d-%d:%d", e.BeginLine, e.BeginColumn, e.EndLine, e.EndColumn),
+			e.ErrorType,
+			e.ErrorTitle,
+			e.ErrorMessage,
+		})
+	}
+	_, err := fmt.Fprintln(w, tw.Render())
+	return err
+}
@@ -11,33 +11,43 @@ import (
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
+
+	"github.com/dsantic/graylog-cli/internal/timeexpr"
 )
 
 const (
-	EnvURL      = "GRAYLOGCTL_URL"
-	EnvAPIBase  = "GRAYLOGCTL_API_BASE"
-	EnvToken    = "GRAYLOGCTL_TOKEN"
-	EnvSession  = "GRAYLOGCTL_SESSION"
-	EnvInsecure = "GRAYLOGCTL_INSECURE"
-	EnvTimeout  = "GRAYLOGCTL_TIMEOUT"
-	EnvFormat   = "GRAYLOGCTL_FORMAT"
-	EnvProfile  = "GRAYLOGCTL_PROFILE"
+	EnvURL       = "GRAYLOGCTL_URL"
+	EnvAPIBase   = "GRAYLOGCTL_API_BASE"
+	EnvToken     = "GRAYLOGCTL_TOKEN"
+	EnvSession   = "GRAYLOGCTL_SESSION"
+	EnvInsecure  = "GRAYLOGCTL_INSECURE"
+	EnvTimeout   = "GRAYLOGCTL_TIMEOUT"
+	EnvFormat    = "GRAYLOGCTL_FORMAT"
+	EnvProfile   = "GRAYLOGCTL_PROFILE"
+	EnvNoCache   = "GRAYLOGCTL_NO_CACHE"
+	EnvRetries   = "GRAYLOGCTL_RETRIES"
+	EnvRateLimit = "GRAYLOGCTL_RATE_LIMIT"
 
 	DefaultProfile = "default"
 	DefaultAPIBase = "/api"
 	DefaultTimeout = 30 * time.Second
 	DefaultFormat  = "table"
+	DefaultRetries = 2
 )
 
 type Config struct {
-	Profiles map[string]Profile `yaml:"profiles"`
+	Profiles map[string]Profile    `yaml:"profiles"`
+	Queries  map[string]SavedQuery `yaml:"queries,omitempty"`
 }
 
 type Profile struct {
-	URL      string      `yaml:"url"`
-	APIBase  string      `yaml:"api_base"`
-	Insecure bool        `yaml:"insecure"`
-	Auth     ProfileAuth `yaml:"auth"`
+	URL       string            `yaml:"url"`
+	APIBase   string            `yaml:"api_base"`
+	Insecure  bool              `yaml:"insecure"`
+	Auth      ProfileAuth       `yaml:"auth"`
+	CacheTTL  map[string]string `yaml:"cache_ttl,omitempty"`
+	Retries   *int              `yaml:"retries,omitempty"`
+	RateLimit float64           `yaml:"rate_limit,omitempty"`
 }
 
 type ProfileAuth struct {
@@ -46,15 +56,47 @@ type ProfileAuth struct {
 }
 
 type Runtime struct {
-	URL      string
-	APIBase  string
-	Token    string
-	Session  string
-	Insecure bool
-	Timeout  time.Duration
-	Format   string
-	Profile  string
-	MaxWidth int
+	URL       string
+	APIBase   string
+	Token     string
+	Session   string
+	Insecure  bool
+	Timeout   time.Duration
+	Format    string
+	Profile   string
+	MaxWidth  int
+	NoCache   bool
+	CacheTTL  map[string]time.Duration
+	DryRun    bool
+	AsCurl    bool
+	Verbose   bool
+	Trace     bool
+	Retries   int
+	RateLimit float64
+	// RetriesSet and RateLimitSet record that the value came from a flag or
+	// the environment, which also overrides other profiles' settings.
+	RetriesSet   bool
+	RateLimitSet bool
+}
+
+// ProfileRetries is the retry count for a client of profile p: an explicit
+// flag or environment value, else the profile's own setting.
+func (r Runtime) ProfileRetries(p Profile) int {
+	if r.RetriesSet {
+		return r.Retries
+	}
+	if p.Retries != nil {
+		return *p.Retries
+	}
+	return DefaultRetries
+}
+
+// ProfileRateLimit is ProfileRetries for the rate limit.
+func (r Runtime) ProfileRateLimit(p Profile) float64 {
+	if r.RateLimitSet {
+		return r.RateLimit
+	}
+	return p.RateLimit
 }
 
 func ConfigPath() (string, error) {
@@ -148,20 +190,71 @@ func Resolve(cmd *cobra.Command, cfg *Config) (Runtime, error) {
 	if err != nil {
 		return Runtime{}, fmt.Errorf("read --max-width: %w", err)
 	}
+	noCache, err := chooseBool(cmd, "no-cache", EnvNoCache, false, false)
+	if err != nil {
+		return Runtime{}, err
+	}
+	cacheTTL, err := ParseCacheTTL(p.CacheTTL)
+	if err != nil {
+		return Runtime{}, fmt.Errorf("profile %q: %w", profile, err)
+	}
+	retries, err := chooseInt(cmd, "retries", EnvRetries, p.Retries, DefaultRetries)
+	if err != nil {
+		return Runtime{}, err
+	}
+	if retries < 0 {
+		return Runtime{}, fmt.Errorf("--retries must be >= 0")
+	}
+	rateLimit, err := chooseFloat(cmd, "rate-limit", EnvRateLimit, p.RateLimit, 0)
+	if err != nil {
+		return Runtime{}, err
+	}
+	if rateLimit < 0 {
+		return Runtime{}, fmt.Errorf("--rate-limit must be >= 0")
+	}
 
 	return Runtime{
-		URL:      url,
-		APIBase:  apiBase,
-		Token:    token,
-		Session:  session,
-		Insecure: insecure,
-		Timeout:  timeout,
-		Format:   format,
-		Profile:  profile,
-		MaxWidth: maxWidth,
+		URL:          url,
+		APIBase:      apiBase,
+		Token:        token,
+		Session:      session,
+		Insecure:     insecure,
+		Timeout:      timeout,
+		Format:       format,
+		Profile:      profile,
+		MaxWidth:     maxWidth,
+		NoCache:      noCache,
+		CacheTTL:     cacheTTL,
+		DryRun:       flagBool(cmd, "dry-run"),
+		AsCurl:       flagBool(cmd, "as-curl"),
+		Verbose:      flagBool(cmd, "verbose"),
+		Trace:        flagBool(cmd, "trace"),
+		Retries:      retries,
+		RateLimit:    rateLimit,
+		RetriesSet:   isSet(cmd, "retries", EnvRetries),
+		RateLimitSet: isSet(cmd, "rate-limit", EnvRateLimit),
 	}, nil
 }
 
+func ParseCacheTTL(raw map[string]string) (map[string]time.Duration, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]time.Duration, len(raw))
+	for name, v := range raw {
+		// Plain Go durations such as 0 or 1.5h stay valid; timeexpr adds d and w.
+		d, err := time.ParseDuration(strings.TrimSpace(v))
+		if err != nil {
+			d, err = timeexpr.ParseDuration(v)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid cache_ttl.%s: %w", name, err)
+		}
+		out[name] = d
+	}
+	return out, nil
+}
+
 func chooseString(cmd *cobra.Command, flagName, envName, profileVal, fallback string) string {
 	if cmd.Flags().Changed(flagName) {
 		v, _ := cmd.Flags().GetString(flagName)
@@ -197,6 +290,60 @@ func chooseBool(cmd *cobra.Command, flagName, envName string, profileVal, fallba
 	return fallback, nil
 }
 
+// flagBool reads a flag-only switch; a flag the command does not define reads
+// as false.
+func isSet(cmd *cobra.Command, flagName, envName string) bool {
+	_, ok := os.LookupEnv(envName)
+	return ok || cmd.Flags().Changed(flagName)
+}
+
+func flagBool(cmd *cobra.Command, name string) bool {
+	v, _ := cmd.Flags().GetBool(name)
+	return v
+}
+
+func chooseInt(cmd *cobra.Command, flagName, envName string, profileVal *int, fallback int) (int, error) {
+	if cmd.Flags().Changed(flagName) {
+		v, err := cmd.Flags().GetInt(flagName)
+		if err != nil {
+			return 0, fmt.Errorf("read --%s: %w", flagName, err)
+		}
+		return v, nil
+	}
+	if raw, ok := os.LookupEnv(envName); ok {
+		v, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s=%q: %w", envName, raw, err)
+		}
+		return v, nil
+	}
+	if profileVal != nil {
+		return *profileVal, nil
+	}
+	return fallback, nil
+}
+
+func chooseFloat(cmd *cobra.Command, flagName, envName string, profileVal, fallback float64) (float64, error) {
+	if cmd.Flags().Changed(flagName) {
+		v, err := cmd.Flags().GetFloat64(flagName)
+		if err != nil {
+			return 0, fmt.Errorf("read --%s: %w", flagName, err)
+		}
+		return v, nil
+	}
+	if raw, ok := os.LookupEnv(envName); ok {
+		v, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s=%q: %w", envName, raw, err)
+		}
+		return v, nil
+	}
+	if profileVal != 0 {
+		return profileVal, nil
+	}
+	return fallback, nil
+}
+
 func chooseDuration(cmd *cobra.Command, flagName, envName string, fallback time.Duration) (time.Duration, error) {
 	if cmd.Flags().Changed(flagName) {
 		raw, err := cmd.Flags().GetString(flagName)
@@ -59,3 +59,88 @@ func TestResolvePrecedenceFlagsEnvConfig(t *testing.T) {
 		t.Fatalf("expected timeout from env, got %s", r.Timeout)
 	}
 }
+
+func TestResolveRetriesAndRateLimit(t *testing.T) {
+	retries := 5
+	cfg := &Config{Profiles: map[string]Profile{
+		"default": {URL: "https://config.example.com", Retries: &retries, RateLimit: 2.5},
+	}}
+
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{Use: "test"}
+		cmd.Flags().String("profile", DefaultProfile, "")
+		cmd.Flags().Int("max-width", 0, "")
+		cmd.Flags().Int("retries", DefaultRetries, "")
+		cmd.Flags().Float64("rate-limit", 0, "")
+		return cmd
+	}
+
+	r, err := Resolve(newCmd(), cfg)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if r.Retries != 5 || r.RateLimit != 2.5 {
+		t.Fatalf("expected profile values, got retries=%d rate=%v", r.Retries, r.RateLimit)
+	}
+
+	cmd := newCmd()
+	if err := cmd.Flags().Set("retries", "0"); err != nil {
+		t.Fatalf("set flag: %v", err)
+	}
+	t.Setenv(EnvRateLimit, "10")
+	r, err = Resolve(cmd, cfg)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if r.Retries != 0 || r.RateLimit != 10 {
+		t.Fatalf("expected flag/env values, got retries=%d rate=%v", r.Retries, r.RateLimit)
+	}
+
+	r, err = Resolve(newCmd(), DefaultConfig())
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if r.Retries != DefaultRetries {
+		t.Fatalf("expected default retries, got %d", r.Retries)
+	}
+}
+
+func TestRuntimeProfileRetriesAndRateLimit(t *testing.T) {
+	t.Parallel()
+
+	retries := 7
+	us := Profile{Retries: &retries, RateLimit: 3}
+	r := Runtime{Retries: 1, RateLimit: 0.5}
+	if got := r.ProfileRetries(us); got != 7 {
+		t.Fatalf("expected the target profile's retries, got %d", got)
+	}
+	if got := r.ProfileRateLimit(us); got != 3 {
+		t.Fatalf("expected the target profile's rate limit, got %v", got)
+	}
+	if got := r.ProfileRetries(Profile{}); got != DefaultRetries {
+		t.Fatalf("expected default retries for a profile without them, got %d", got)
+	}
+
+	r.RetriesSet, r.RateLimitSet = true, true
+	if r.ProfileRetries(us) != 1 || r.ProfileRateLimit(us) != 0.5 {
+		t.Fatalf("expected explicit values to win, got retries=%d rate=%v", r.ProfileRetries(us), r.ProfileRateLimit(us))
+	}
+}
+
+func TestParseCacheTTL(t *testing.T) {
+	t.Parallel()
+
+	got, err := ParseCacheTTL(map[string]string{"streams": "1d", "fields": "2w", "nodes": "0", "inputs": "1.5h"})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	want := map[string]time.Duration{"streams": 24 * time.Hour, "fields": 14 * 24 * time.Hour, "nodes": 0, "inputs": 90 * time.Minute}
+	for k, v := range want {
+		if got[k] != v {
+			t.Fatalf("cache_ttl.%s: expected %s, got %s", k, v, got[k])
+		}
+	}
+	if _, err := ParseCacheTTL(map[string]string{"streams": "1y"}); err == nil {
+		t.Fatal("expected an error for an unknown unit")
+	}
+}
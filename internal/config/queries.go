@@ -0,0 +1,99 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SavedQuery is a named search template. Query, fields and streams may use
+// ${var} placeholders filled from --var or the defaults in Vars.
+type SavedQuery struct {
+	Description string            `yaml:"description,omitempty"`
+	Query       string            `yaml:"query"`
+	Fields      []string          `yaml:"fields,omitempty"`
+	Streams     []string          `yaml:"streams,omitempty"`
+	Since       string            `yaml:"since,omitempty"`
+	Vars        map[string]string `yaml:"vars,omitempty"`
+}
+
+var placeholderRe = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_.-]*)\}`)
+
+// Placeholders lists the variable names used by the template, sorted.
+func (q SavedQuery) Placeholders() []string {
+	seen := map[string]bool{}
+	for _, s := range q.templates() {
+		for _, m := range placeholderRe.FindAllStringSubmatch(s, -1) {
+			seen[m[1]] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Expand fills the placeholders from vars, falling back to the saved
+// defaults. Every placeholder must end up with a value.
+func (q SavedQuery) Expand(vars map[string]string) (SavedQuery, error) {
+	values := make(map[string]string, len(q.Vars)+len(vars))
+	for k, v := range q.Vars {
+		values[k] = v
+	}
+	for k, v := range vars {
+		values[k] = v
+	}
+	var missing []string
+	for _, name := range q.Placeholders() {
+		if _, ok := values[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return SavedQuery{}, fmt.Errorf("missing value for %s (use --var name=value)", strings.Join(missing, ", "))
+	}
+
+	expand := func(s string) string {
+		return placeholderRe.ReplaceAllStringFunc(s, func(m string) string {
+			return values[placeholderRe.FindStringSubmatch(m)[1]]
+		})
+	}
+	out := q
+	out.Query = expand(q.Query)
+	out.Fields = expandAll(q.Fields, expand)
+	out.Streams = expandAll(q.Streams, expand)
+	return out, nil
+}
+
+func (q SavedQuery) templates() []string {
+	out := append([]string{q.Query}, q.Fields...)
+	return append(out, q.Streams...)
+}
+
+func expandAll(list []string, expand func(string) string) []string {
+	if list == nil {
+		return nil
+	}
+	out := make([]string, len(list))
+	for i, s := range list {
+		out[i] = expand(s)
+	}
+	return out
+}
+
+// ParseVars parses repeated name=value arguments.
+func ParseVars(args []string) (map[string]string, error) {
+	vars := make(map[string]string, len(args))
+	for _, arg := range args {
+		name, value, ok := strings.Cut(arg, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid variable %q (want name=value)", arg)
+		}
+		vars[name] = value
+	}
+	return vars, nil
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSavedQueryExpand(t *testing.T) {
+	t.Parallel()
+
+	q := SavedQuery{
+		Query:   `service:${service} AND level:<=${level}`,
+		Fields:  []string{"timestamp", "${extra}"},
+		Streams: []string{"${env}-nginx"},
+		Vars:    map[string]string{"level": "3", "extra": "message"},
+	}
+	if got := q.Placeholders(); !reflect.DeepEqual(got, []string{"env", "extra", "level", "service"}) {
+		t.Fatalf("unexpected placeholders %v", got)
+	}
+
+	if _, err := q.Expand(map[string]string{"service": "sync"}); err == nil {
+		t.Fatalf("expected error for missing env")
+	}
+
+	out, err := q.Expand(map[string]string{"service": "sync", "env": "prod", "level": "4"})
+	if err != nil {
+		t.Fatalf("expand: %v", err)
+	}
+	if out.Query != "service:sync AND level:<=4" {
+		t.Fatalf("unexpected query %q", out.Query)
+	}
+	if !reflect.DeepEqual(out.Fields, []string{"timestamp", "message"}) || !reflect.DeepEqual(out.Streams, []string{"prod-nginx"}) {
+		t.Fatalf("unexpected fields/streams %v %v", out.Fields, out.Streams)
+	}
+	if q.Query != `service:${service} AND level:<=${level}` {
+		t.Fatalf("expand must not modify the template")
+	}
+}
+
+func TestParseVars(t *testing.T) {
+	t.Parallel()
+
+	vars, err := ParseVars([]string{"service=sync", "q=a=b", "empty="})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if vars["service"] != "sync" || vars["q"] != "a=b" || vars["empty"] != "" {
+		t.Fatalf("unexpected vars %v", vars)
+	}
+	if _, err := ParseVars([]string{"novalue"}); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestSavedQueriesRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := DefaultConfig()
+	cfg.Queries = map[string]SavedQuery{"errors": {Query: "level:3 AND service:${service}", Since: "15m", Vars: map[string]string{"service": "sync"}}}
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	loaded, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Queries["errors"], cfg.Queries["errors"]) {
+		t.Fatalf("unexpected queries %+v", loaded.Queries)
+	}
+}
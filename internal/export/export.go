@@ -0,0 +1,173 @@
+package export
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/dsantic/graylog-cli/internal/checkpoint"
+	"github.com/dsantic/graylog-cli/internal/graylog"
+	"github.com/dsantic/graylog-cli/internal/output"
+)
+
+type Exporter interface {
+	ExportMessages(ctx context.Context, req graylog.ExportRequest) (io.ReadCloser, error)
+}
+
+// Sliced describes one sliced export. State holds the window still to be
+// exported and is advanced (and saved to Checkpoint, if set) after every
+// completed slice.
+type Sliced struct {
+	Request    graylog.ExportRequest
+	State      *checkpoint.State
+	Resumed    bool
+	Slice      time.Duration
+	Descending bool
+	Format     string
+	Limit      int
+	Output     string
+	Stdout     io.Writer
+	Checkpoint string
+}
+
+// Run exports the remaining slices, newest first when Descending is set so
+// the file keeps the requested timestamp order across slices. The output
+// file is only opened (and truncated) once the first slice has been
+// received, so a dry run or a failing first request leaves it untouched.
+func (s *Sliced) Run(ctx context.Context, e Exporter) error {
+	state := s.State
+	to := state.To
+	if !state.NextTo.IsZero() {
+		to = state.NextTo
+	}
+	ranges := graylog.SplitTimerange(state.NextFrom, to, s.Slice)
+	if s.Descending {
+		for i, j := 0, len(ranges)-1; i < j; i, j = i+1, j-1 {
+			ranges[i], ranges[j] = ranges[j], ranges[i]
+		}
+	}
+
+	var file *os.File
+	defer func() {
+		if file != nil {
+			file.Close()
+		}
+	}()
+	counter := &countingWriter{w: s.Stdout}
+	bw := bufio.NewWriter(counter)
+	open := func() error {
+		if s.Output == "-" || file != nil {
+			return nil
+		}
+		var err error
+		if s.Resumed {
+			file, err = openForResume(s.Output, state.OutputOffset)
+		} else {
+			file, err = os.Create(s.Output)
+			if err != nil {
+				err = fmt.Errorf("create output %s: %w", s.Output, err)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		counter.w = file
+		return nil
+	}
+
+	req := s.Request
+	for _, r := range ranges {
+		if s.Limit > 0 && state.Exported >= s.Limit {
+			break
+		}
+		req.Timerange = graylog.AbsoluteTimerange(r[0], r[1])
+		if s.Limit > 0 {
+			req.Limit = s.Limit - state.Exported
+		}
+		body, err := e.ExportMessages(ctx, req)
+		if err != nil {
+			return err
+		}
+		if err := open(); err != nil {
+			body.Close()
+			return err
+		}
+		n, err := output.CopyCSVExport(bw, body, s.Format, state.OutputOffset+counter.n == 0)
+		body.Close()
+		if err != nil {
+			return err
+		}
+		if err := bw.Flush(); err != nil {
+			return fmt.Errorf("write output: %w", err)
+		}
+		if file != nil {
+			if err := file.Sync(); err != nil {
+				return fmt.Errorf("sync output %s: %w", s.Output, err)
+			}
+		}
+
+		if s.Descending {
+			state.NextTo = r[0].Add(-time.Millisecond)
+		} else {
+			state.NextFrom = r[1].Add(time.Millisecond)
+		}
+		state.OutputOffset += counter.n
+		counter.n = 0
+		state.Exported += n
+		if s.Checkpoint != "" {
+			if err := checkpoint.Save(s.Checkpoint, state); err != nil {
+				return err
+			}
+		}
+	}
+	if err := open(); err != nil {
+		return err
+	}
+
+	state.Done = true
+	if s.Checkpoint != "" {
+		return checkpoint.Save(s.Checkpoint, state)
+	}
+	return nil
+}
+
+// openForResume drops anything written after the last completed slice so a
+// partially exported slice is not duplicated.
+func openForResume(path string, offset int64) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open output %s: %w", path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("stat output %s: %w", path, err)
+	}
+	if info.Size() < offset {
+		f.Close()
+		return nil, fmt.Errorf("output %s is shorter (%d bytes) than the checkpoint offset %d", path, info.Size(), offset)
+	}
+	if err := f.Truncate(offset); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("truncate output %s: %w", path, err)
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("seek output %s: %w", path, err)
+	}
+	return f, nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
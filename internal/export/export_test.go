@@ -0,0 +1,187 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dsantic/graylog-cli/internal/checkpoint"
+	"github.com/dsantic/graylog-cli/internal/graylog"
+)
+
+// newExportServer serves /views/search/messages as CSV, one row per message
+// inside the requested range in the requested timestamp order. Request
+// number failAt (1-based) answers 500.
+func newExportServer(t *testing.T, messages []time.Time, failAt int64, calls *atomic.Int64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/views/search/messages" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+			return
+		}
+		if calls.Add(1) == failAt {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = io.WriteString(w, `{"message":"boom"}`)
+			return
+		}
+		var req graylog.ExportRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		from, to, err := req.Timerange.Bounds(time.Now())
+		if err != nil {
+			t.Errorf("bounds: %v", err)
+		}
+		var hits []time.Time
+		for _, m := range messages {
+			if !m.Before(from) && !m.After(to) {
+				hits = append(hits, m)
+			}
+		}
+		desc := len(req.Sort) == 1 && req.Sort[0].Field == "timestamp" && req.Sort[0].Order == "DESC"
+		sort.Slice(hits, func(i, j int) bool { return hits[i].Before(hits[j]) != desc })
+		w.Header().Set("Content-Type", "text/csv")
+		fmt.Fprintln(w, `"timestamp","message"`)
+		for _, h := range hits {
+			fmt.Fprintf(w, "%q,%q\n", h.Format(graylog.TimestampLayout), "m")
+		}
+	}))
+}
+
+func newTestClient(t *testing.T, url string, dryRun graylog.DryRunMode) *graylog.Client {
+	t.Helper()
+	c, err := graylog.NewClient(graylog.ClientConfig{BaseURL: url, APIBase: "/api", DryRun: dryRun, DryRunOut: io.Discard})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	return c
+}
+
+func newSliced(from, to time.Time, out, ckpt string, resumed bool, state *checkpoint.State) *Sliced {
+	if state == nil {
+		state = &checkpoint.State{From: from, To: to, NextFrom: from}
+	}
+	return &Sliced{
+		Request:    graylog.ExportRequest{Sort: []graylog.ExportSort{{Field: "timestamp", Order: "DESC"}}},
+		State:      state,
+		Resumed:    resumed,
+		Slice:      time.Hour,
+		Descending: true,
+		Format:     "csv",
+		Output:     out,
+		Stdout:     io.Discard,
+		Checkpoint: ckpt,
+	}
+}
+
+func TestSlicedResumeAfterFailedSlice(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	to := from.Add(3*time.Hour - time.Millisecond)
+	var messages []time.Time
+	for i := 0; i < 3; i++ {
+		base := from.Add(time.Duration(i) * time.Hour)
+		messages = append(messages, base.Add(10*time.Minute), base.Add(40*time.Minute))
+	}
+
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out.csv")
+	ckpt := filepath.Join(dir, "out.ckpt")
+
+	var calls atomic.Int64
+	srv := newExportServer(t, messages, 2, &calls)
+	defer srv.Close()
+	c := newTestClient(t, srv.URL, graylog.DryRunOff)
+
+	var apiErr *graylog.APIError
+	if err := newSliced(from, to, out, ckpt, false, nil).Run(context.Background(), c); !errors.As(err, &apiErr) {
+		t.Fatalf("expected the second slice to fail, got %v", err)
+	}
+	state, err := checkpoint.Load(ckpt)
+	if err != nil || state == nil {
+		t.Fatalf("expected a checkpoint after the first slice, got %+v (err %v)", state, err)
+	}
+	if state.Exported != 2 || !state.NextTo.Equal(from.Add(2*time.Hour-time.Millisecond)) {
+		t.Fatalf("unexpected checkpoint: %+v", state)
+	}
+
+	// A partially written slice must be dropped on resume.
+	f, err := os.OpenFile(out, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("open output: %v", err)
+	}
+	_, _ = io.WriteString(f, "\"2026-02-18T01:40:00.000Z\",\"partial\"\n")
+	f.Close()
+
+	if err := newSliced(from, to, out, ckpt, true, state).Run(context.Background(), c); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if state.Exported != len(messages) || !state.Done {
+		t.Fatalf("unexpected final state: %+v", state)
+	}
+
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if len(lines) != len(messages)+1 || lines[0] != "timestamp,message" {
+		t.Fatalf("expected one header and %d rows, got:\n%s", len(messages), b)
+	}
+	for i, line := range lines[1:] {
+		want := messages[len(messages)-1-i].Format(graylog.TimestampLayout) + ",m"
+		if line != want {
+			t.Fatalf("row %d: expected %q, got %q\n%s", i, want, line, b)
+		}
+	}
+}
+
+func TestSlicedLeavesOutputUntouchedUntilFirstSlice(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	to := from.Add(2 * time.Hour)
+
+	tests := []struct {
+		name   string
+		failAt int64
+		dryRun graylog.DryRunMode
+	}{
+		{name: "api error", failAt: 1},
+		{name: "dry run", dryRun: graylog.DryRunRequest},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			out := filepath.Join(t.TempDir(), "out.csv")
+			if err := os.WriteFile(out, []byte("previous export\n"), 0o644); err != nil {
+				t.Fatalf("write output: %v", err)
+			}
+			var calls atomic.Int64
+			srv := newExportServer(t, []time.Time{from.Add(time.Minute)}, tc.failAt, &calls)
+			defer srv.Close()
+
+			if err := newSliced(from, to, out, "", false, nil).Run(context.Background(), newTestClient(t, srv.URL, tc.dryRun)); err == nil {
+				t.Fatal("expected an error")
+			}
+			b, err := os.ReadFile(out)
+			if err != nil {
+				t.Fatalf("read output: %v", err)
+			}
+			if string(b) != "previous export\n" {
+				t.Fatalf("output was modified: %q", b)
+			}
+		})
+	}
+}
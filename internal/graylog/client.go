@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"sort"
 	"strings"
 	"time"
 )
@@ -22,14 +23,39 @@ type ClientConfig struct {
 	Session  string
 	Insecure bool
 	Timeout  time.Duration
+	Cache    ResponseCache
+	// DryRun prints requests to DryRunOut instead of sending them.
+	DryRun    DryRunMode
+	DryRunOut io.Writer
+	Retry     RetryPolicy
+	// RateLimit caps requests per second; 0 disables it.
+	RateLimit float64
+	// Trace logs requests with timings to TraceOut (default stderr).
+	Trace    TraceLevel
+	TraceOut io.Writer
+}
+
+// ResponseCache stores bodies of successful GET responses. Implementations
+// decide which paths are cacheable and for how long.
+type ResponseCache interface {
+	Get(apiPath string) ([]byte, bool)
+	Put(apiPath string, body []byte)
 }
 
 type Client struct {
-	baseURL string
-	apiBase string
-	token   string
-	session string
-	http    *http.Client
+	baseURL    string
+	apiBase    string
+	token      string
+	session    string
+	http       *http.Client
+	streamHTTP *http.Client
+	cache      ResponseCache
+	insecure   bool
+	dryRun     DryRunMode
+	dryRunOut  io.Writer
+	retry      RetryPolicy
+	limiter    *rateLimiter
+	tracer     *tracer
 }
 
 type APIError struct {
@@ -69,6 +95,8 @@ func NewClient(cfg ClientConfig) (*Client, error) {
 
 	transport := http.DefaultTransport.(*http.Transport).Clone()
 	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: cfg.Insecure} //nolint:gosec
+	streamTransport := transport.Clone()
+	streamTransport.ResponseHeaderTimeout = cfg.Timeout
 
 	return &Client{
 		baseURL: strings.TrimRight(cfg.BaseURL, "/"),
@@ -79,6 +107,14 @@ func NewClient(cfg ClientConfig) (*Client, error) {
 			Timeout:   cfg.Timeout,
 			Transport: transport,
 		},
+		streamHTTP: &http.Client{Transport: streamTransport},
+		cache:      cfg.Cache,
+		insecure:   cfg.Insecure,
+		dryRun:     cfg.DryRun,
+		dryRunOut:  cfg.DryRunOut,
+		retry:      cfg.Retry.withDefaults(),
+		limiter:    newRateLimiter(cfg.RateLimit),
+		tracer:     newTracer(cfg.Trace, cfg.TraceOut),
 	}, nil
 }
 
@@ -89,24 +125,166 @@ func (c *Client) URLFor(apiPath string) string {
 }
 
 func (c *Client) Do(ctx context.Context, method, apiPath string, reqBody any, out any) error {
-	endpoint := c.URLFor(apiPath)
-	var body io.Reader
+	cacheable := method == http.MethodGet && c.cache != nil && c.dryRun == DryRunOff
+	if cacheable {
+		if payload, ok := c.cache.Get(apiPath); ok {
+			if c.tracer != nil {
+				c.tracer.traceCached(c.URLFor(apiPath), len(payload))
+			}
+			if out == nil {
+				return nil
+			}
+			if err := json.Unmarshal(payload, out); err == nil {
+				return nil
+			}
+		}
+	}
+
+	resp, endpoint, err := c.send(ctx, c.http, method, apiPath, reqBody, "application/json")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	payload, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response %s: %w", endpoint, err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return responseError(apiPath, endpoint, resp.StatusCode, payload)
+	}
+	if cacheable && len(payload) > 0 {
+		c.cache.Put(apiPath, payload)
+	}
+
+	if out == nil || len(payload) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(payload, out); err != nil {
+		return fmt.Errorf("decode response from %s: %w", endpoint, err)
+	}
+	return nil
+}
+
+// doDecode sends a request like Do but hands the successful response body to
+// decode instead of buffering it.
+func (c *Client) doDecode(ctx context.Context, method, apiPath string, reqBody any, decode func(io.Reader) error) error {
+	resp, endpoint, err := c.send(ctx, c.http, method, apiPath, reqBody, "application/json")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		payload, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("read response %s: %w", endpoint, err)
+		}
+		return responseError(apiPath, endpoint, resp.StatusCode, payload)
+	}
+	if err := decode(resp.Body); err != nil {
+		return fmt.Errorf("decode response from %s: %w", endpoint, err)
+	}
+	return nil
+}
+
+// CallRequest is a request with a pre-encoded body, used for endpoints that
+// have no typed wrapper.
+type CallRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Body   []byte
+	// ContentType defaults to application/json.
+	ContentType string
+	Accept      string
+}
+
+// CallResponse is an undecoded response body with its status and headers.
+type CallResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Call sends an arbitrary request and returns the response undecoded.
+// Non-2xx responses are returned as *APIError, as with Do.
+func (c *Client) Call(ctx context.Context, cr CallRequest) (CallResponse, error) {
+	if cr.Accept == "" {
+		cr.Accept = "application/json"
+	}
+	resp, endpoint, err := c.sendPayload(ctx, c.http, cr)
+	if err != nil {
+		return CallResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	payload, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return CallResponse{}, fmt.Errorf("read response %s: %w", endpoint, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return CallResponse{}, responseError(cr.Path, endpoint, resp.StatusCode, payload)
+	}
+	return CallResponse{StatusCode: resp.StatusCode, Header: resp.Header, Body: payload}, nil
+}
+
+// Stream sends a request like Do but hands the caller the open response body
+// for incremental reading. It is not bound by the client timeout; cancel ctx
+// to abort a long transfer.
+func (c *Client) Stream(ctx context.Context, method, apiPath string, reqBody any, accept string) (io.ReadCloser, error) {
+	resp, endpoint, err := c.send(ctx, c.streamHTTP, method, apiPath, reqBody, accept)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		payload, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		return nil, responseError(apiPath, endpoint, resp.StatusCode, payload)
+	}
+	return resp.Body, nil
+}
+
+func (c *Client) send(ctx context.Context, hc *http.Client, method, apiPath string, reqBody any, accept string) (*http.Response, string, error) {
+	var payload []byte
 	if reqBody != nil {
 		b, err := json.Marshal(reqBody)
 		if err != nil {
-			return fmt.Errorf("marshal request to %s: %w", endpoint, err)
+			endpoint := c.URLFor(apiPath)
+			return nil, endpoint, fmt.Errorf("marshal request to %s: %w", endpoint, err)
 		}
-		body = bytes.NewReader(b)
+		payload = b
+	}
+	return c.sendPayload(ctx, hc, CallRequest{Method: method, Path: apiPath, Body: payload, Accept: accept})
+}
+
+// sendPayload sends an already encoded request, applying auth, dry run,
+// rate limiting and retries.
+func (c *Client) sendPayload(ctx context.Context, hc *http.Client, cr CallRequest) (*http.Response, string, error) {
+	method, apiPath, payload := cr.Method, cr.Path, cr.Body
+	endpoint := c.URLFor(apiPath)
+	if len(cr.Query) > 0 {
+		endpoint += "?" + cr.Query.Encode()
+	}
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
 	if err != nil {
-		return fmt.Errorf("create request %s %s: %w", method, endpoint, err)
+		return nil, endpoint, fmt.Errorf("create request %s %s: %w", method, endpoint, err)
 	}
 
-	req.Header.Set("Accept", "application/json")
+	contentType := cr.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	req.Header.Set("Accept", cr.Accept)
 	if method != http.MethodGet {
-		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Content-Type", contentType)
 		req.Header.Set("X-Requested-By", "cli")
 	}
 
@@ -116,36 +294,80 @@ func (c *Client) Do(ctx context.Context, method, apiPath string, reqBody any, ou
 		req.SetBasicAuth(c.session, "session")
 	}
 
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return fmt.Errorf("request %s %s: %w", method, endpoint, err)
+	if c.dryRun != DryRunOff {
+		if err := c.explain(req, payload); err != nil {
+			return nil, endpoint, err
+		}
+		return nil, endpoint, ErrDryRun
 	}
-	defer resp.Body.Close()
 
-	payload, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("read response %s: %w", endpoint, err)
+	retries := 0
+	if c.retry.allows(method, apiPath) {
+		retries = c.retry.MaxRetries
 	}
+	for attempt := 0; ; attempt++ {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, endpoint, fmt.Errorf("request %s %s: %w", method, endpoint, err)
+		}
+		attemptReq := req
+		if attempt > 0 {
+			attemptReq = req.Clone(ctx)
+			if payload != nil {
+				attemptReq.Body = io.NopCloser(bytes.NewReader(payload))
+			}
+		}
+		var rt *requestTrace
+		if c.tracer != nil {
+			attemptReq, rt = c.tracer.begin(attemptReq, payload, attempt)
+		}
+		resp, err := hc.Do(attemptReq)
+		if rt != nil {
+			if err != nil {
+				rt.failed(err)
+			} else {
+				rt.wrap(resp)
+			}
+		}
+		if attempt >= retries {
+			if err != nil && attempt > 0 {
+				return nil, endpoint, fmt.Errorf("request %s %s failed after %d attempts: %w", method, endpoint, attempt+1, err)
+			}
+			if err != nil {
+				return nil, endpoint, fmt.Errorf("request %s %s: %w", method, endpoint, err)
+			}
+			return resp, endpoint, nil
+		}
 
-	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		apiErr := parseAPIError(resp.StatusCode, endpoint, payload)
-		if strings.HasSuffix(apiPath, "/search/messages") && resp.StatusCode == http.StatusNotFound {
-			apiErr.Message = "Your Graylog may not expose Search Scripting API. Check version >= 6.x and permissions. Consider using views search or legacy endpoints."
+		delay := c.retry.backoff(attempt)
+		switch {
+		case err != nil:
+			if !retryableError(ctx, err) {
+				return nil, endpoint, fmt.Errorf("request %s %s: %w", method, endpoint, err)
+			}
+		case retryableStatus(resp.StatusCode):
+			if d, ok := retryAfter(resp.Header, time.Now()); ok {
+				delay = d
+			}
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		default:
+			return resp, endpoint, nil
 		}
-		if strings.HasSuffix(apiPath, "/search/messages") && resp.StatusCode == http.StatusForbidden {
-			apiErr.Message = apiErr.Message + " Guidance: token/session user must have permission to run searches."
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, endpoint, fmt.Errorf("request %s %s: %w", method, endpoint, err)
 		}
-		return apiErr
 	}
+}
 
-	if out == nil || len(payload) == 0 {
-		return nil
+func responseError(apiPath, endpoint string, status int, payload []byte) *APIError {
+	apiErr := parseAPIError(status, endpoint, payload)
+	if isScriptingAPIPath(apiPath) && status == http.StatusNotFound {
+		apiErr.Message = "Your Graylog may not expose Search Scripting API. Check version >= 6.x and permissions. Consider using views search or legacy endpoints."
 	}
-
-	if err := json.Unmarshal(payload, out); err != nil {
-		return fmt.Errorf("decode response from %s: %w", endpoint, err)
+	if isScriptingAPIPath(apiPath) && status == http.StatusForbidden {
+		apiErr.Message = apiErr.Message + " Guidance: token/session user must have permission to run searches."
 	}
-	return nil
+	return apiErr
 }
 
 func (c *Client) CreateSession(ctx context.Context, username, password string) (SessionResponse, error) {
@@ -160,18 +382,252 @@ func (c *Client) CreateSession(ctx context.Context, username, password string) (
 	return resp, nil
 }
 
+// SearchMessages runs one search page. The response is decoded in a single
+// streaming pass; Raw is left empty (see SearchMessagesRaw).
 func (c *Client) SearchMessages(ctx context.Context, req SearchMessagesRequest) (SearchMessagesResponse, error) {
+	var rows [][]any
+	if req.Size > 0 {
+		rows = make([][]any, 0, req.Size)
+	}
+	resp, err := c.StreamSearchMessages(ctx, req, func(row []any) error {
+		rows = append(rows, row)
+		return nil
+	})
+	if err != nil {
+		return SearchMessagesResponse{}, err
+	}
+	resp.DataRows = rows
+	return resp, nil
+}
+
+// StreamSearchMessages runs one search page and passes each row to fn as it
+// is decoded. The returned response holds schema and metadata only. An error
+// from fn stops decoding and is returned as is.
+func (c *Client) StreamSearchMessages(ctx context.Context, req SearchMessagesRequest, fn func(row []any) error) (SearchMessagesResponse, error) {
+	var (
+		resp  SearchMessagesResponse
+		fnErr error
+	)
+	err := c.doDecode(ctx, http.MethodPost, "/search/messages", req, func(r io.Reader) error {
+		var err error
+		resp, err = decodeSearchResponse(r, func(row []any) error {
+			fnErr = fn(row)
+			return fnErr
+		})
+		return err
+	})
+	if fnErr != nil {
+		return SearchMessagesResponse{}, fnErr
+	}
+	if err != nil {
+		return SearchMessagesResponse{}, err
+	}
+	return resp, nil
+}
+
+// SearchMessagesRaw is SearchMessages with Raw filled in with the undecoded
+// response document. It costs one generic decode of the whole body.
+func (c *Client) SearchMessagesRaw(ctx context.Context, req SearchMessagesRequest) (SearchMessagesResponse, error) {
 	var raw map[string]any
 	if err := c.Do(ctx, http.MethodPost, "/search/messages", req, &raw); err != nil {
 		return SearchMessagesResponse{}, err
 	}
-	b, _ := json.Marshal(raw)
-	var parsed SearchMessagesResponse
-	if err := json.Unmarshal(b, &parsed); err != nil {
+	resp, err := searchResponseFromMap(raw)
+	if err != nil {
 		return SearchMessagesResponse{}, fmt.Errorf("parse search response: %w", err)
 	}
-	parsed.Raw = raw
-	return parsed, nil
+	return resp, nil
+}
+
+// ErrResultWindow is returned by SearchMessagesPages when more rows may
+// match but the next page would start beyond the index result window.
+var ErrResultWindow = fmt.Errorf("stopped at the result window of %d messages; more may match", DefaultResultWindow)
+
+// SearchMessagesPages pages through a search starting at req.From, calling fn
+// for every non-empty page. It stops on an empty or short page, or once
+// maxResults rows were fetched (0 means no limit), and returns the row count.
+// Pages never reach past DefaultResultWindow; a full page ending there
+// returns ErrResultWindow.
+func (c *Client) SearchMessagesPages(ctx context.Context, req SearchMessagesRequest, maxResults int, fn func(SearchMessagesResponse) error) (int, error) {
+	return c.searchPages(ctx, req, maxResults, DefaultResultWindow, fn)
+}
+
+func (c *Client) searchPages(ctx context.Context, req SearchMessagesRequest, maxResults, window int, fn func(SearchMessagesResponse) error) (int, error) {
+	if req.Size <= 0 {
+		return 0, errors.New("search page size must be > 0")
+	}
+	fetched := 0
+	for {
+		if req.From >= window {
+			return fetched, ErrResultWindow
+		}
+		page := req
+		if maxResults > 0 && maxResults-fetched < page.Size {
+			page.Size = maxResults - fetched
+		}
+		page.Size = min(page.Size, window-page.From)
+		resp, err := c.SearchMessages(ctx, page)
+		if err != nil {
+			return fetched, err
+		}
+		n := len(resp.DataRows)
+		if n == 0 {
+			return fetched, nil
+		}
+		if err := fn(resp); err != nil {
+			return fetched, err
+		}
+		fetched += n
+		if n < page.Size || (maxResults > 0 && fetched >= maxResults) {
+			return fetched, nil
+		}
+		req.From += n
+	}
+}
+
+func (c *Client) Aggregate(ctx context.Context, req AggregateRequest) (AggregateResponse, error) {
+	var resp AggregateResponse
+	if err := c.Do(ctx, http.MethodPost, "/search/aggregate", req, &resp); err != nil {
+		return AggregateResponse{}, err
+	}
+	return resp, nil
+}
+
+func (c *Client) ValidateQuery(ctx context.Context, req QueryValidationRequest) (QueryValidationResponse, error) {
+	var resp QueryValidationResponse
+	if err := c.Do(ctx, http.MethodPost, "/search/validate", req, &resp); err != nil {
+		return QueryValidationResponse{}, err
+	}
+	return resp, nil
+}
+
+func (c *Client) GetMessage(ctx context.Context, index, id string) (MessageResponse, error) {
+	var resp MessageResponse
+	if err := c.Do(ctx, http.MethodGet, path.Join("/messages", index, id), nil, &resp); err != nil {
+		return MessageResponse{}, err
+	}
+	if resp.Index == "" {
+		resp.Index = index
+	}
+	return resp, nil
+}
+
+// FindMessage locates a message by id without knowing its index: a search on
+// _id yields its timestamp, and the indices whose range covers that timestamp
+// are tried in turn.
+func (c *Client) FindMessage(ctx context.Context, id string) (MessageResponse, error) {
+	hit, err := c.SearchMessages(ctx, SearchMessagesRequest{
+		Query:     fmt.Sprintf("_id:%q", id),
+		Fields:    []string{"timestamp"},
+		Size:      1,
+		Timerange: SearchTimerange{Type: "relative", Range: 0},
+	})
+	if err != nil {
+		return MessageResponse{}, fmt.Errorf("look up message %s: %w", id, err)
+	}
+	rows := NormalizeSearchResponse(hit).Rows
+	if len(rows) == 0 {
+		return MessageResponse{}, fmt.Errorf("message %s not found", id)
+	}
+	ts, ok := ParseTimestamp(rows[0]["timestamp"])
+	if !ok {
+		return MessageResponse{}, fmt.Errorf("message %s has no usable timestamp (%v)", id, rows[0]["timestamp"])
+	}
+
+	var ranges IndexRangesResponse
+	if err := c.Do(ctx, http.MethodGet, "/system/indices/ranges", nil, &ranges); err != nil {
+		return MessageResponse{}, fmt.Errorf("list index ranges: %w", err)
+	}
+	// Closed ranges covering the timestamp go first, then open ones such as
+	// the current write index, which has no range until it is rotated.
+	var covering, open []string
+	for _, r := range ranges.Ranges {
+		begin, okBegin := ParseTimestamp(r.Begin)
+		end, okEnd := ParseTimestamp(r.End)
+		switch {
+		case !okBegin || !okEnd || begin.Unix() == 0 || end.Unix() == 0 || end.Before(begin):
+			open = append(open, r.IndexName)
+		case !ts.Before(begin) && !ts.After(end):
+			covering = append(covering, r.IndexName)
+		}
+	}
+	for _, index := range append(covering, open...) {
+		msg, err := c.GetMessage(ctx, index, id)
+		if err == nil {
+			return msg, nil
+		}
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+			return MessageResponse{}, err
+		}
+	}
+	return MessageResponse{}, fmt.Errorf("message %s matched a search but no index range covering %s contains it", id, ts.Format(TimestampLayout))
+}
+
+func (c *Client) ListFields(ctx context.Context) ([]FieldType, error) {
+	var fields []FieldType
+	err := c.Do(ctx, http.MethodGet, "/views/fields", nil, &fields)
+	if err == nil {
+		sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+		return fields, nil
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		return nil, err
+	}
+
+	var legacy struct {
+		Fields []string `json:"fields"`
+	}
+	if err := c.Do(ctx, http.MethodGet, "/system/fields", nil, &legacy); err != nil {
+		return nil, err
+	}
+	sort.Strings(legacy.Fields)
+	fields = make([]FieldType, 0, len(legacy.Fields))
+	for _, name := range legacy.Fields {
+		fields = append(fields, FieldType{Name: name})
+	}
+	return fields, nil
+}
+
+func (c *Client) CountMessages(ctx context.Context, query string, streams []string, tr SearchTimerange) (int, error) {
+	resp, err := c.Aggregate(ctx, AggregateRequest{
+		Query:     query,
+		Streams:   streams,
+		Timerange: tr,
+		Metrics:   []AggregateMetric{{Function: "count"}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(resp.DataRows) == 0 || len(resp.DataRows[0]) == 0 {
+		return 0, nil
+	}
+	row := resp.DataRows[0]
+	n, ok := row[len(row)-1].(float64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected count value %v in aggregate response", row[len(row)-1])
+	}
+	return int(n), nil
+}
+
+func (c *Client) ExportMessages(ctx context.Context, req ExportRequest) (io.ReadCloser, error) {
+	return c.Stream(ctx, http.MethodPost, "/views/search/messages", req, "text/csv")
+}
+
+// isScriptingAPIPath matches the Search Scripting API endpoints only, not the
+// views export at /views/search/messages.
+func isScriptingAPIPath(apiPath string) bool {
+	switch normalizeAPIPath(apiPath) {
+	case "/search/messages", "/search/aggregate":
+		return true
+	}
+	return false
+}
+
+// normalizeAPIPath gives apiPath exactly one leading and no trailing slash.
+func normalizeAPIPath(apiPath string) string {
+	return "/" + strings.Trim(apiPath, "/")
 }
 
 func parseAPIError(status int, endpoint string, body []byte) *APIError {
@@ -3,8 +3,13 @@ package graylog
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 )
 
@@ -94,3 +99,421 @@ func TestURLJoinBehavior(t *testing.T) {
 		t.Fatalf("url mismatch: got %q want %q", got, want)
 	}
 }
+
+func TestSearchMessagesPagesStopsOnEmptyPage(t *testing.T) {
+	t.Parallel()
+
+	var offsets []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req SearchMessagesRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		offsets = append(offsets, req.From)
+		rows := [][]any{}
+		if req.From < 4 {
+			rows = [][]any{{"a"}, {"b"}}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"schema":   []map[string]any{{"name": "message"}},
+			"datarows": rows,
+		})
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL, APIBase: "/api"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	pages := 0
+	n, err := c.SearchMessagesPages(context.Background(), SearchMessagesRequest{Size: 2}, 0, func(SearchMessagesResponse) error {
+		pages++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("search pages: %v", err)
+	}
+	if n != 4 || pages != 2 {
+		t.Fatalf("expected 4 rows in 2 pages, got %d rows in %d pages", n, pages)
+	}
+	if len(offsets) != 3 || offsets[2] != 4 {
+		t.Fatalf("unexpected offsets: %v", offsets)
+	}
+}
+
+func TestSearchMessagesPagesMaxResults(t *testing.T) {
+	t.Parallel()
+
+	var sizes []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req SearchMessagesRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		sizes = append(sizes, req.Size)
+		rows := make([][]any, req.Size)
+		for i := range rows {
+			rows[i] = []any{"x"}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"schema": []map[string]any{{"name": "message"}}, "datarows": rows})
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL, APIBase: "/api"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	n, err := c.SearchMessagesPages(context.Background(), SearchMessagesRequest{Size: 3}, 5, func(SearchMessagesResponse) error { return nil })
+	if err != nil {
+		t.Fatalf("search pages: %v", err)
+	}
+	if n != 5 {
+		t.Fatalf("expected 5 rows, got %d", n)
+	}
+	if len(sizes) != 2 || sizes[1] != 2 {
+		t.Fatalf("unexpected page sizes: %v", sizes)
+	}
+}
+
+func TestSearchMessagesPagesStopsAtResultWindow(t *testing.T) {
+	t.Parallel()
+
+	var last SearchMessagesRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&last); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if last.From+last.Size > DefaultResultWindow {
+			t.Errorf("from+size %d exceeds the result window", last.From+last.Size)
+		}
+		rows := make([][]any, last.Size)
+		for i := range rows {
+			rows[i] = []any{"x"}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"schema": []map[string]any{{"name": "message"}}, "datarows": rows})
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL, APIBase: "/api"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	n, err := c.SearchMessagesPages(context.Background(), SearchMessagesRequest{From: 9000, Size: 300}, 0, func(SearchMessagesResponse) error { return nil })
+	if !errors.Is(err, ErrResultWindow) {
+		t.Fatalf("expected ErrResultWindow, got %v", err)
+	}
+	if n != 1000 || last.From != 9900 || last.Size != 100 {
+		t.Fatalf("expected 1000 rows ending with a 100 row page at 9900, got %d rows, last page %d+%d", n, last.From, last.Size)
+	}
+}
+
+func TestCountMessages(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/search/aggregate" {
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+		var req AggregateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if len(req.Metrics) != 1 || req.Metrics[0].Function != "count" || len(req.GroupBy) != 0 {
+			t.Fatalf("unexpected aggregate request: %+v", req)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"schema":[{"column_type":"metric","type":"numeric","function":"count","name":"metric: count()"}],"datarows":[[42]]}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL, APIBase: "/api"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	n, err := c.CountMessages(context.Background(), "level:3", nil, SearchTimerange{Type: "relative", Range: 300})
+	if err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if n != 42 {
+		t.Fatalf("expected 42, got %d", n)
+	}
+}
+
+func TestValidateQuery(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/search/validate" {
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"ERROR","explanations":[{"error_type":"QUERY_PARSING_ERROR","error_title":"Query parsing error","error_message":"Cannot parse 'source:(nginx'","begin_line":1,"begin_column":7,"end_line":1,"end_column":13}]}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL, APIBase: "/api"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	resp, err := c.ValidateQuery(context.Background(), QueryValidationRequest{Query: "source:(nginx"})
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if !resp.Invalid() || len(resp.Explanations) != 1 || resp.Explanations[0].BeginColumn != 7 {
+		t.Fatalf("unexpected validation response: %+v", resp)
+	}
+}
+
+func TestListFieldsFallsBackToSystemFields(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/views/fields":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"type":"ApiError","message":"HTTP 404 Not Found"}`))
+		case "/api/system/fields":
+			_, _ = w.Write([]byte(`{"fields":["source","message","level"]}`))
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL, APIBase: "/api"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	fields, err := c.ListFields(context.Background())
+	if err != nil {
+		t.Fatalf("list fields: %v", err)
+	}
+	if len(fields) != 3 || fields[0].Name != "level" || fields[2].Name != "source" {
+		t.Fatalf("unexpected fields: %+v", fields)
+	}
+}
+
+func TestGetMessage(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/messages/graylog_7/abc-123" {
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"index":"graylog_7","message":{"_id":"abc-123","source":"nginx-1","timestamp":"2026-02-18T10:00:00.000Z"}}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL, APIBase: "/api"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	msg, err := c.GetMessage(context.Background(), "graylog_7", "abc-123")
+	if err != nil {
+		t.Fatalf("get message: %v", err)
+	}
+	if msg.Index != "graylog_7" || msg.Message["source"] != "nginx-1" {
+		t.Fatalf("unexpected message: %+v", msg)
+	}
+}
+
+func TestFindMessageTriesCoveringIndices(t *testing.T) {
+	t.Parallel()
+
+	var tried []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/search/messages":
+			var req SearchMessagesRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			if req.Query != `_id:"abc"` || req.Timerange.Type != "relative" || req.Timerange.Range != 0 {
+				t.Errorf("unexpected lookup search: %+v", req)
+			}
+			_, _ = w.Write([]byte(`{"schema":[{"name":"timestamp"}],"datarows":[["2026-02-18T10:00:00.000Z"]]}`))
+		case r.URL.Path == "/api/system/indices/ranges":
+			_, _ = w.Write([]byte(`{"total":3,"ranges":[
+				{"index_name":"graylog_1","begin":"2026-02-01T00:00:00.000Z","end":"2026-02-10T00:00:00.000Z"},
+				{"index_name":"graylog_2","begin":"2026-02-10T00:00:00.000Z","end":"2026-02-20T00:00:00.000Z"},
+				{"index_name":"restored_2","begin":"2026-02-10T00:00:00.000Z","end":"2026-02-20T00:00:00.000Z"}]}`))
+		default:
+			tried = append(tried, r.URL.Path)
+			if r.URL.Path != "/api/messages/restored_2/abc" {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"message":"not found"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"index":"restored_2","message":{"_id":"abc"}}`))
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL, APIBase: "/api"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	msg, err := c.FindMessage(context.Background(), "abc")
+	if err != nil {
+		t.Fatalf("find message: %v", err)
+	}
+	if msg.Index != "restored_2" {
+		t.Fatalf("unexpected index %q", msg.Index)
+	}
+	if len(tried) != 2 || tried[0] != "/api/messages/graylog_2/abc" {
+		t.Fatalf("unexpected lookups: %v", tried)
+	}
+}
+
+func TestFindMessageTriesCurrentWriteIndex(t *testing.T) {
+	t.Parallel()
+
+	var tried []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/search/messages":
+			_, _ = w.Write([]byte(`{"schema":[{"name":"timestamp"}],"datarows":[["2026-02-25T10:00:00.000Z"]]}`))
+		case r.URL.Path == "/api/system/indices/ranges":
+			// The write index reports an empty range at the Unix epoch.
+			_, _ = w.Write([]byte(`{"total":2,"ranges":[
+				{"index_name":"graylog_3","begin":"1970-01-01T00:00:00.000Z","end":"1970-01-01T00:00:00.000Z"},
+				{"index_name":"graylog_2","begin":"2026-02-10T00:00:00.000Z","end":"2026-02-20T00:00:00.000Z"}]}`))
+		default:
+			tried = append(tried, r.URL.Path)
+			if r.URL.Path != "/api/messages/graylog_3/abc" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(`{"index":"graylog_3","message":{"_id":"abc"}}`))
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL, APIBase: "/api"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	msg, err := c.FindMessage(context.Background(), "abc")
+	if err != nil {
+		t.Fatalf("find message: %v", err)
+	}
+	if msg.Index != "graylog_3" || len(tried) != 1 {
+		t.Fatalf("unexpected result %q after lookups %v", msg.Index, tried)
+	}
+}
+
+type memoryCache map[string][]byte
+
+func (m memoryCache) Get(apiPath string) ([]byte, bool) {
+	b, ok := m[apiPath]
+	return b, ok
+}
+
+func (m memoryCache) Put(apiPath string, body []byte) {
+	m[apiPath] = body
+}
+
+func TestDoServesGETFromCache(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"streams":[{"id":"s1","title":"nginx"}]}`))
+	}))
+	defer srv.Close()
+
+	cache := memoryCache{}
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL, Token: "t", Cache: cache})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		var out map[string]any
+		if err := c.Do(context.Background(), http.MethodGet, "/streams", nil, &out); err != nil {
+			t.Fatalf("do: %v", err)
+		}
+		if _, ok := out["streams"]; !ok {
+			t.Fatalf("unexpected response %v", out)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected one request, got %d", calls)
+	}
+
+	if err := c.Do(context.Background(), http.MethodPost, "/streams", map[string]any{}, nil); err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected POST to bypass the cache, got %d calls", calls)
+	}
+}
+
+func TestCallSendsRawBodyAndQuery(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"type":"ApiError","message":"not found"}`))
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPut || r.URL.Path != "/api/system/inputs/1" || r.URL.Query().Get("force") != "true" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		if string(body) != "title: raw" || r.Header.Get("Content-Type") != "text/plain" {
+			t.Errorf("unexpected body %q (%s)", body, r.Header.Get("Content-Type"))
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("done"))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL, APIBase: "/api", Token: "t"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	resp, err := c.Call(context.Background(), CallRequest{
+		Method:      http.MethodPut,
+		Path:        "/system/inputs/1",
+		Query:       url.Values{"force": {"true"}},
+		Body:        []byte("title: raw"),
+		ContentType: "text/plain",
+	})
+	if err != nil {
+		t.Fatalf("call: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || string(resp.Body) != "done" {
+		t.Fatalf("unexpected response %d %q", resp.StatusCode, resp.Body)
+	}
+
+	_, err = c.Call(context.Background(), CallRequest{Method: http.MethodGet, Path: "/missing"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.Message != "not found" {
+		t.Fatalf("expected API error, got %v", err)
+	}
+}
+
+func TestResponseErrorScriptingGuidanceOnlyForScriptingPaths(t *testing.T) {
+	t.Parallel()
+
+	for _, p := range []string{"/search/messages", "search/messages", "/search/aggregate/"} {
+		if err := responseError(p, "u", http.StatusNotFound, nil); !strings.Contains(err.Message, "Search Scripting API") {
+			t.Fatalf("expected scripting guidance for %s, got %q", p, err.Message)
+		}
+	}
+	err := responseError("/views/search/messages", "u", http.StatusNotFound, []byte(`{"message":"HTTP 404 Not Found"}`))
+	if strings.Contains(err.Message, "Scripting") || err.Message != "HTTP 404 Not Found" {
+		t.Fatalf("unexpected export message %q", err.Message)
+	}
+	if err := responseError("views/search/messages/", "u", http.StatusForbidden, nil); strings.Contains(err.Error(), "Guidance") {
+		t.Fatalf("unexpected export guidance %q", err.Error())
+	}
+}
@@ -0,0 +1,147 @@
+package graylog
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type CountDelta struct {
+	Value    string   `json:"value"`
+	Current  int      `json:"current"`
+	Baseline int      `json:"baseline"`
+	Delta    int      `json:"delta"`
+	Change   *float64 `json:"change_percent"`
+	New      bool     `json:"new,omitempty"`
+}
+
+// PercentChange returns the relative change from baseline to current, or nil
+// when the baseline is zero.
+func PercentChange(current, baseline int) *float64 {
+	if baseline == 0 {
+		return nil
+	}
+	v := 100 * float64(current-baseline) / float64(baseline)
+	return &v
+}
+
+// CompareCounts pairs the per-value counts of two windows, largest growth
+// first. Values with a zero baseline are marked New, so both maps should hold
+// counts for the same values (see CompareCountsBy).
+func CompareCounts(current, baseline map[string]int) []CountDelta {
+	out := make([]CountDelta, 0, len(current)+len(baseline))
+	for value, cur := range current {
+		base := baseline[value]
+		out = append(out, CountDelta{Value: value, Current: cur, Baseline: base, Delta: cur - base, Change: PercentChange(cur, base), New: base == 0 && cur > 0})
+	}
+	for value, base := range baseline {
+		if _, ok := current[value]; ok {
+			continue
+		}
+		out = append(out, CountDelta{Value: value, Baseline: base, Delta: -base, Change: PercentChange(0, base)})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Delta != out[j].Delta {
+			return out[i].Delta > out[j].Delta
+		}
+		return out[i].Value < out[j].Value
+	})
+	return out
+}
+
+// CompareCountsBy compares per-value counts of field between two windows. The
+// limit most frequent values of each window are taken, then the values only
+// one window ranked are counted exactly in the other, so a value just below
+// the cut in the baseline is not mistaken for a new one.
+func (c *Client) CompareCountsBy(ctx context.Context, query string, streams []string, current, baseline SearchTimerange, field string, limit int) ([]CountDelta, error) {
+	cur, err := c.CountMessagesBy(ctx, query, streams, current, field, limit)
+	if err != nil {
+		return nil, fmt.Errorf("count current window by %s: %w", field, err)
+	}
+	base, err := c.CountMessagesBy(ctx, query, streams, baseline, field, limit)
+	if err != nil {
+		return nil, fmt.Errorf("count baseline window by %s: %w", field, err)
+	}
+	if err := c.fillCounts(ctx, query, streams, baseline, field, base, missingValues(cur, base)); err != nil {
+		return nil, fmt.Errorf("count baseline window by %s: %w", field, err)
+	}
+	if err := c.fillCounts(ctx, query, streams, current, field, cur, missingValues(base, cur)); err != nil {
+		return nil, fmt.Errorf("count current window by %s: %w", field, err)
+	}
+	return CompareCounts(cur, base), nil
+}
+
+// maxValuesPerQuery bounds the terms OR-ed into one value filter.
+const maxValuesPerQuery = 100
+
+// fillCounts adds the counts of values to counts, recording 0 for values
+// without matches.
+func (c *Client) fillCounts(ctx context.Context, query string, streams []string, tr SearchTimerange, field string, counts map[string]int, values []string) error {
+	for len(values) > 0 {
+		batch := values[:min(len(values), maxValuesPerQuery)]
+		values = values[len(batch):]
+		found, err := c.CountMessagesBy(ctx, valueFilterQuery(query, field, batch), streams, tr, field, len(batch))
+		if err != nil {
+			return err
+		}
+		for _, v := range batch {
+			counts[v] = found[v]
+		}
+	}
+	return nil
+}
+
+// missingValues returns the keys of from that are not in in, sorted.
+func missingValues(from, in map[string]int) []string {
+	var out []string
+	for v := range from {
+		if _, ok := in[v]; !ok {
+			out = append(out, v)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// valueFilterQuery restricts query to messages whose field is one of values,
+// e.g. `(level:3) AND source:("a" OR "b")`.
+func valueFilterQuery(query, field string, values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(v) + `"`
+	}
+	filter := field + ":(" + strings.Join(quoted, " OR ") + ")"
+	query = strings.TrimSpace(query)
+	if query == "" || query == "*" {
+		return filter
+	}
+	return "(" + query + ") AND " + filter
+}
+
+// CountMessagesBy counts matching messages per value of field, for the limit
+// most frequent values.
+func (c *Client) CountMessagesBy(ctx context.Context, query string, streams []string, tr SearchTimerange, field string, limit int) (map[string]int, error) {
+	resp, err := c.Aggregate(ctx, AggregateRequest{
+		Query:     query,
+		Streams:   streams,
+		Timerange: tr,
+		GroupBy:   []AggregateGrouping{{Field: field, Limit: limit}},
+		Metrics:   []AggregateMetric{{Function: "count"}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int, len(resp.DataRows))
+	for _, row := range resp.DataRows {
+		if len(row) < 2 {
+			continue
+		}
+		n, ok := row[len(row)-1].(float64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected count value %v in aggregate response", row[len(row)-1])
+		}
+		counts[fmt.Sprint(row[0])] += int(n)
+	}
+	return counts, nil
+}
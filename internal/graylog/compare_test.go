@@ -0,0 +1,150 @@
+package graylog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompareCounts(t *testing.T) {
+	t.Parallel()
+
+	got := CompareCounts(
+		map[string]int{"api": 30, "sync": 12, "web": 5},
+		map[string]int{"api": 10, "web": 8, "cron": 4},
+	)
+	want := []struct {
+		value string
+		delta int
+		isNew bool
+	}{{"api", 20, false}, {"sync", 12, true}, {"web", -3, false}, {"cron", -4, false}}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected deltas %+v", got)
+	}
+	for i, w := range want {
+		if got[i].Value != w.value || got[i].Delta != w.delta || got[i].New != w.isNew {
+			t.Fatalf("row %d: got %+v want %+v", i, got[i], w)
+		}
+	}
+	if got[0].Change == nil || *got[0].Change != 200 {
+		t.Fatalf("expected +200%% for api, got %v", got[0].Change)
+	}
+	if got[1].Change != nil {
+		t.Fatalf("expected no percentage for a new value")
+	}
+	if got[3].Change == nil || *got[3].Change != -100 {
+		t.Fatalf("expected -100%% for a vanished value, got %v", got[3].Change)
+	}
+}
+
+func TestCountMessagesBy(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req AggregateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if len(req.GroupBy) != 1 || req.GroupBy[0].Field != "source" || req.GroupBy[0].Limit != 5 {
+			t.Fatalf("unexpected grouping %+v", req.GroupBy)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"schema":[{"column_type":"grouping","field":"source"},{"column_type":"metric","function":"count"}],"datarows":[["web-1",7],["web-2",3]]}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL, Token: "t"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	counts, err := c.CountMessagesBy(context.Background(), "*", nil, SearchTimerange{Type: "relative", Range: 60}, "source", 5)
+	if err != nil {
+		t.Fatalf("count by: %v", err)
+	}
+	if len(counts) != 2 || counts["web-1"] != 7 || counts["web-2"] != 3 {
+		t.Fatalf("unexpected counts %v", counts)
+	}
+}
+
+func TestCompareCountsByCountsValuesBelowTheCut(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 2, 18, 10, 0, 0, 0, time.UTC)
+	current := AbsoluteTimerange(base, base.Add(time.Hour))
+	baseline := AbsoluteTimerange(base.Add(-24*time.Hour), base.Add(-23*time.Hour))
+	data := map[string]map[string]int{
+		current.From:  {"api": 30, "sync": 12, "web": 5},
+		baseline.From: {"api": 10, "web": 8, "sync": 7},
+	}
+	filter := regexp.MustCompile(`source:\((.*)\)$`)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req AggregateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		counts := data[req.Timerange.From]
+		var values []string
+		if m := filter.FindStringSubmatch(req.Query); m != nil {
+			for _, v := range strings.Split(m[1], " OR ") {
+				values = append(values, strings.Trim(v, `"`))
+			}
+		} else {
+			for v := range counts {
+				values = append(values, v)
+			}
+		}
+		sort.Slice(values, func(i, j int) bool { return counts[values[i]] > counts[values[j]] })
+		rows := [][]any{}
+		for _, v := range values {
+			if counts[v] > 0 && len(rows) < req.GroupBy[0].Limit {
+				rows = append(rows, []any{v, counts[v]})
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"datarows": rows})
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL, Token: "t"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	// With a top-2 cut, sync is ranked third in the baseline and web third in
+	// the current window.
+	got, err := c.CompareCountsBy(context.Background(), "*", nil, current, baseline, "source", 2)
+	if err != nil {
+		t.Fatalf("compare: %v", err)
+	}
+	want := []CountDelta{
+		{Value: "api", Current: 30, Baseline: 10, Delta: 20},
+		{Value: "sync", Current: 12, Baseline: 7, Delta: 5},
+		{Value: "web", Current: 5, Baseline: 8, Delta: -3},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected deltas %+v", got)
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Value != w.Value || g.Current != w.Current || g.Baseline != w.Baseline || g.Delta != w.Delta || g.New {
+			t.Fatalf("row %d: got %+v want %+v", i, g, w)
+		}
+	}
+}
+
+func TestValueFilterQuery(t *testing.T) {
+	t.Parallel()
+
+	if got := valueFilterQuery("level:3", "source", []string{"a", `b "c"`}); got != `(level:3) AND source:("a" OR "b \"c\"")` {
+		t.Fatalf("unexpected query %s", got)
+	}
+	if got := valueFilterQuery("*", "source", []string{"a"}); got != `source:("a")` {
+		t.Fatalf("unexpected query %s", got)
+	}
+}
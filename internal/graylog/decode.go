@@ -0,0 +1,104 @@
+package graylog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// decodeSearchResponse reads a search response token by token. Each data row
+// is decoded on its own and passed to fn, so the body is never buffered or
+// converted to an intermediate map. The returned response carries schema and
+// metadata but no rows.
+func decodeSearchResponse(r io.Reader, fn func(row []any) error) (SearchMessagesResponse, error) {
+	var resp SearchMessagesResponse
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return resp, err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return resp, err
+		}
+		key, _ := tok.(string)
+		switch key {
+		case "schema":
+			err = dec.Decode(&resp.Schema)
+		case "metadata":
+			err = dec.Decode(&resp.Metadata)
+		case "datarows":
+			err = decodeRows(dec, fn)
+		default:
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		}
+		if err != nil {
+			return resp, err
+		}
+	}
+	return resp, expectDelim(dec, '}')
+}
+
+func decodeRows(dec *json.Decoder, fn func(row []any) error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '[' {
+		return fmt.Errorf("datarows: expected array, got %v", tok)
+	}
+	for dec.More() {
+		var row []any
+		if err := dec.Decode(&row); err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != want {
+		return fmt.Errorf("expected %q, got %v", want, tok)
+	}
+	return nil
+}
+
+// searchResponseFromMap builds the typed response from a generic decode,
+// reusing the row slices instead of re-encoding them.
+func searchResponseFromMap(raw map[string]any) (SearchMessagesResponse, error) {
+	resp := SearchMessagesResponse{Raw: raw}
+	if schema, ok := raw["schema"]; ok && schema != nil {
+		b, err := json.Marshal(schema)
+		if err != nil {
+			return resp, err
+		}
+		if err := json.Unmarshal(b, &resp.Schema); err != nil {
+			return resp, fmt.Errorf("schema: %w", err)
+		}
+	}
+	if rows, ok := raw["datarows"].([]any); ok {
+		resp.DataRows = make([][]any, 0, len(rows))
+		for i, r := range rows {
+			row, ok := r.([]any)
+			if !ok && r != nil {
+				return resp, fmt.Errorf("datarows[%d]: expected array, got %T", i, r)
+			}
+			resp.DataRows = append(resp.DataRows, row)
+		}
+	}
+	if md, ok := raw["metadata"].(map[string]any); ok {
+		resp.Metadata = md
+	}
+	return resp, nil
+}
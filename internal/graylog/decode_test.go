@@ -0,0 +1,181 @@
+package graylog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const sampleSearchResponse = `{"schema":[{"column_type":"field","type":"string","field":"timestamp","name":"field: timestamp"},{"column_type":"field","type":"string","field":"message","name":"field: message"}],"datarows":[["2024-01-01T00:00:00.000Z","a"],["2024-01-01T00:00:01.000Z","b"]],"metadata":{"effective_timerange":{"type":"relative","range":300}},"extra":{"ignored":[1,2,3]}}`
+
+func TestDecodeSearchResponse(t *testing.T) {
+	t.Parallel()
+
+	var rows [][]any
+	resp, err := decodeSearchResponse(strings.NewReader(sampleSearchResponse), func(row []any) error {
+		rows = append(rows, row)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Schema) != 2 || resp.Schema[1].Field != "message" {
+		t.Fatalf("unexpected schema: %+v", resp.Schema)
+	}
+	if len(rows) != 2 || rows[1][1] != "b" {
+		t.Fatalf("unexpected rows: %v", rows)
+	}
+	if resp.Metadata["effective_timerange"] == nil {
+		t.Fatalf("missing metadata: %v", resp.Metadata)
+	}
+	if resp.DataRows != nil || resp.Raw != nil {
+		t.Fatalf("rows and raw must not be collected")
+	}
+}
+
+func TestDecodeSearchResponseErrors(t *testing.T) {
+	t.Parallel()
+
+	stop := errors.New("stop")
+	calls := 0
+	_, err := decodeSearchResponse(strings.NewReader(sampleSearchResponse), func([]any) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Fatalf("expected callback error after one row, got %v (%d calls)", err, calls)
+	}
+
+	for _, body := range []string{`[]`, `{"datarows":{}}`, `{"datarows":[["a"]`, `{"schema":[`} {
+		if _, err := decodeSearchResponse(strings.NewReader(body), func([]any) error { return nil }); err == nil {
+			t.Errorf("expected error for %s", body)
+		}
+	}
+	if _, err := decodeSearchResponse(strings.NewReader(`{"datarows":null}`), func([]any) error { return nil }); err != nil {
+		t.Errorf("null datarows: %v", err)
+	}
+}
+
+func TestSearchMessagesVariants(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(sampleSearchResponse))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL, APIBase: "/api", Token: "t"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	ctx := context.Background()
+
+	resp, err := c.SearchMessages(ctx, SearchMessagesRequest{Size: 10})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(resp.DataRows) != 2 || resp.Raw != nil {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	raw, err := c.SearchMessagesRaw(ctx, SearchMessagesRequest{})
+	if err != nil {
+		t.Fatalf("search raw: %v", err)
+	}
+	if raw.Raw["extra"] == nil || len(raw.DataRows) != 2 || len(raw.Schema) != 2 {
+		t.Fatalf("unexpected raw response: %+v", raw)
+	}
+
+	stop := errors.New("stop")
+	if _, err := c.StreamSearchMessages(ctx, SearchMessagesRequest{}, func([]any) error { return stop }); err != stop {
+		t.Fatalf("expected callback error unwrapped, got %v", err)
+	}
+}
+
+func benchmarkSearchPayload(rows int) []byte {
+	var b bytes.Buffer
+	b.WriteString(`{"schema":[{"column_type":"field","type":"string","field":"timestamp","name":"field: timestamp"},{"column_type":"field","type":"string","field":"source","name":"field: source"},{"column_type":"field","type":"string","field":"message","name":"field: message"},{"column_type":"field","type":"numeric","field":"took_ms","name":"field: took_ms"}],"datarows":[`)
+	for i := 0; i < rows; i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `["2024-01-01T00:00:%02d.000Z","host-%d","GET /api/items/%d returned 200 in %dms",%d]`, i%60, i%16, i, i%500, i%500)
+	}
+	b.WriteString(`],"metadata":{"effective_timerange":{"type":"relative","range":300}}}`)
+	return b.Bytes()
+}
+
+// decodeSearchResponseLegacy is the previous decode path: a generic map, then a
+// re-marshal into the typed response.
+func decodeSearchResponseLegacy(payload []byte) (SearchMessagesResponse, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return SearchMessagesResponse{}, err
+	}
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return SearchMessagesResponse{}, err
+	}
+	var parsed SearchMessagesResponse
+	if err := json.Unmarshal(b, &parsed); err != nil {
+		return SearchMessagesResponse{}, err
+	}
+	parsed.Raw = raw
+	return parsed, nil
+}
+
+func BenchmarkDecodeSearchResponse(b *testing.B) {
+	payload := benchmarkSearchPayload(10000)
+
+	b.Run("legacy", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(payload)))
+		for i := 0; i < b.N; i++ {
+			if _, err := decodeSearchResponseLegacy(payload); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("collect", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(payload)))
+		for i := 0; i < b.N; i++ {
+			rows := make([][]any, 0, 10000)
+			if _, err := decodeSearchResponse(bytes.NewReader(payload), func(row []any) error {
+				rows = append(rows, row)
+				return nil
+			}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("stream", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(payload)))
+		for i := 0; i < b.N; i++ {
+			if _, err := decodeSearchResponse(bytes.NewReader(payload), func([]any) error { return nil }); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("raw", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(payload)))
+		for i := 0; i < b.N; i++ {
+			var raw map[string]any
+			if err := json.Unmarshal(payload, &raw); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := searchResponseFromMap(raw); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
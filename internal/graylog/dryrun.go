@@ -0,0 +1,141 @@
+package graylog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+)
+
+type DryRunMode int
+
+const (
+	DryRunOff DryRunMode = iota
+	// DryRunRequest prints method, URL, headers and body.
+	DryRunRequest
+	// DryRunCurl prints an equivalent curl command.
+	DryRunCurl
+)
+
+// ErrDryRun is returned instead of a response when the client only prints
+// requests. Commands stop at the first request they would have sent.
+var ErrDryRun = errors.New("dry run: request not sent")
+
+const redacted = "<redacted>"
+
+// redactedBodyKeys are top-level JSON body fields never printed in dry runs.
+var redactedBodyKeys = []string{"password"}
+
+func (c *Client) explain(req *http.Request, body []byte) error {
+	w := c.dryRunOut
+	if w == nil {
+		w = os.Stdout
+	}
+	body = redactBody(body)
+	var err error
+	if c.dryRun == DryRunCurl {
+		_, err = fmt.Fprintln(w, c.curlCommand(req, body))
+	} else {
+		err = writeRequest(w, req, body)
+	}
+	if err != nil {
+		return fmt.Errorf("print request: %w", err)
+	}
+	return nil
+}
+
+func writeRequest(w io.Writer, req *http.Request, body []byte) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s\n", req.Method, req.URL)
+	for _, name := range sortedHeaderNames(req.Header) {
+		value := req.Header.Get(name)
+		if name == "Authorization" {
+			value = redactAuthorization(value)
+		}
+		fmt.Fprintf(&b, "%s: %s\n", name, value)
+	}
+	if len(body) > 0 {
+		var pretty bytes.Buffer
+		if json.Indent(&pretty, body, "", "  ") == nil {
+			body = pretty.Bytes()
+		}
+		fmt.Fprintf(&b, "\n%s\n", body)
+	}
+	b.WriteString("\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// curlCommand renders a runnable curl command. Credentials are taken from the
+// GRAYLOGCTL_TOKEN or GRAYLOGCTL_SESSION environment variable at run time.
+func (c *Client) curlCommand(req *http.Request, body []byte) string {
+	parts := []string{"curl"}
+	if c.insecure {
+		parts = append(parts, "-k")
+	}
+	parts = append(parts, "-X", req.Method, shellQuote(req.URL.String()))
+	if c.token != "" {
+		parts = append(parts, "-u", `"${GRAYLOGCTL_TOKEN}:token"`)
+	} else if c.session != "" {
+		parts = append(parts, "-u", `"${GRAYLOGCTL_SESSION}:session"`)
+	}
+	for _, name := range sortedHeaderNames(req.Header) {
+		if name == "Authorization" {
+			continue
+		}
+		parts = append(parts, "-H", shellQuote(name+": "+req.Header.Get(name)))
+	}
+	if len(body) > 0 {
+		parts = append(parts, "--data-raw", shellQuote(string(body)))
+	}
+	return strings.Join(parts, " ")
+}
+
+func sortedHeaderNames(h http.Header) []string {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func redactAuthorization(v string) string {
+	if scheme, _, ok := strings.Cut(v, " "); ok {
+		return scheme + " " + redacted
+	}
+	return redacted
+}
+
+func redactBody(body []byte) []byte {
+	var obj map[string]json.RawMessage
+	if len(body) == 0 || json.Unmarshal(body, &obj) != nil {
+		return body
+	}
+	changed := false
+	for _, key := range redactedBodyKeys {
+		if _, ok := obj[key]; ok {
+			obj[key] = json.RawMessage(`"` + redacted + `"`)
+			changed = true
+		}
+	}
+	if !changed {
+		return body
+	}
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(obj); err != nil {
+		return body
+	}
+	return bytes.TrimSpace(out.Bytes())
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
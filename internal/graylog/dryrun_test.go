@@ -0,0 +1,81 @@
+package graylog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDryRunPrintsRequestWithoutSending(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL)
+	}))
+	defer srv.Close()
+
+	var out bytes.Buffer
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL, Token: "secret-token", DryRun: DryRunRequest, DryRunOut: &out})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	err = c.Do(context.Background(), http.MethodPost, "/system/sessions", SessionRequest{Username: "admin", Password: "hunter2"}, nil)
+	if !errors.Is(err, ErrDryRun) {
+		t.Fatalf("expected ErrDryRun, got %v", err)
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		"POST " + srv.URL + "/api/system/sessions\n",
+		"Authorization: Basic <redacted>\n",
+		"X-Requested-By: cli\n",
+		`"username": "admin"`,
+		`"password": "<redacted>"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("output missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "secret-token") || strings.Contains(got, "hunter2") {
+		t.Fatalf("credentials leaked:\n%s", got)
+	}
+}
+
+func TestDryRunAsCurl(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	c, err := NewClient(ClientConfig{BaseURL: "https://graylog.example.com", Session: "sid", Insecure: true, DryRun: DryRunCurl, DryRunOut: &out})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	req := SearchMessagesRequest{Query: "msg:it's", Fields: []string{"message"}, Timerange: SearchTimerange{Type: "relative", Range: 60}}
+	if _, err := c.SearchMessages(context.Background(), req); !errors.Is(err, ErrDryRun) {
+		t.Fatalf("expected ErrDryRun, got %v", err)
+	}
+
+	want := `curl -k -X POST 'https://graylog.example.com/api/search/messages' -u "${GRAYLOGCTL_SESSION}:session" ` +
+		`-H 'Accept: application/json' -H 'Content-Type: application/json' -H 'X-Requested-By: cli' ` +
+		`--data-raw '{"query":"msg:it'\''s","fields":["message"],"from":0,"size":0,"timerange":{"type":"relative","range":60}}'` + "\n"
+	if out.String() != want {
+		t.Fatalf("unexpected curl command:\n got %s\nwant %s", out.String(), want)
+	}
+}
+
+func TestDryRunSkipsCache(t *testing.T) {
+	t.Parallel()
+
+	cache := memoryCache{"/streams": []byte(`{"streams":[]}`)}
+	var out bytes.Buffer
+	c, err := NewClient(ClientConfig{BaseURL: "https://graylog.example.com", Token: "t", Cache: cache, DryRun: DryRunRequest, DryRunOut: &out})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	if err := c.Do(context.Background(), http.MethodGet, "/streams", nil, &map[string]any{}); !errors.Is(err, ErrDryRun) {
+		t.Fatalf("expected ErrDryRun, got %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package graylog
+
+import "fmt"
+
+// MergeSearchResponses combines per-source results into one response. Every
+// row gets a leading tagColumn holding its source name, columns are aligned by
+// name, and rows are sorted by sortField.
+func MergeSearchResponses(names []string, results map[string]SearchMessagesResponse, tagColumn, sortField string, desc bool) SearchMessagesResponse {
+	merged := SearchMessagesResponse{Schema: []SearchSchemaColumn{{Name: tagColumn, Field: tagColumn}}}
+	columns := map[string]int{}
+	for _, name := range names {
+		for i, col := range results[name].Schema {
+			key := columnKey(col, i)
+			if _, ok := columns[key]; !ok {
+				columns[key] = len(merged.Schema)
+				merged.Schema = append(merged.Schema, col)
+			}
+		}
+	}
+
+	for _, name := range names {
+		resp := results[name]
+		for _, raw := range resp.DataRows {
+			row := make([]any, len(merged.Schema))
+			row[0] = name
+			for i, col := range resp.Schema {
+				if i < len(raw) {
+					row[columns[columnKey(col, i)]] = raw[i]
+				}
+			}
+			merged.DataRows = append(merged.DataRows, row)
+		}
+	}
+	SortDataRows(merged.Schema, merged.DataRows, sortField, desc)
+	return merged
+}
+
+func columnKey(col SearchSchemaColumn, i int) string {
+	if col.Name != "" {
+		return col.Name
+	}
+	if col.Field != "" {
+		return col.Field
+	}
+	return fmt.Sprintf("col_%d", i)
+}
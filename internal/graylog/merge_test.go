@@ -0,0 +1,38 @@
+package graylog
+
+import "testing"
+
+func TestMergeSearchResponses(t *testing.T) {
+	t.Parallel()
+
+	results := map[string]SearchMessagesResponse{
+		"eu": {
+			Schema:   []SearchSchemaColumn{{Name: "timestamp"}, {Name: "message"}},
+			DataRows: [][]any{{"2026-02-18T10:00:03.000Z", "eu-late"}, {"2026-02-18T10:00:01.000Z", "eu-early"}},
+		},
+		"us": {
+			Schema:   []SearchSchemaColumn{{Name: "message"}, {Name: "timestamp"}},
+			DataRows: [][]any{{"us-mid", "2026-02-18T10:00:02.000Z"}},
+		},
+	}
+	merged := MergeSearchResponses([]string{"eu", "us"}, results, "_profile", "timestamp", true)
+
+	if len(merged.Schema) != 3 || merged.Schema[0].Name != "_profile" || merged.Schema[1].Name != "timestamp" {
+		t.Fatalf("unexpected schema: %+v", merged.Schema)
+	}
+	want := [][]any{
+		{"eu", "2026-02-18T10:00:03.000Z", "eu-late"},
+		{"us", "2026-02-18T10:00:02.000Z", "us-mid"},
+		{"eu", "2026-02-18T10:00:01.000Z", "eu-early"},
+	}
+	if len(merged.DataRows) != len(want) {
+		t.Fatalf("expected %d rows, got %d", len(want), len(merged.DataRows))
+	}
+	for i, row := range want {
+		for j, v := range row {
+			if merged.DataRows[i][j] != v {
+				t.Fatalf("row %d: got %v want %v", i, merged.DataRows[i], row)
+			}
+		}
+	}
+}
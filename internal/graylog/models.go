@@ -1,6 +1,10 @@
 package graylog
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 type SessionRequest struct {
 	Username string `json:"username"`
@@ -34,9 +38,11 @@ type SearchMessagesRequest struct {
 }
 
 type SearchSchemaColumn struct {
-	Name  string `json:"name"`
-	Field string `json:"field"`
-	Type  string `json:"type"`
+	Name       string `json:"name"`
+	Field      string `json:"field"`
+	Type       string `json:"type"`
+	ColumnType string `json:"column_type,omitempty"`
+	Function   string `json:"function,omitempty"`
 }
 
 type SearchMessagesResponse struct {
@@ -52,9 +58,207 @@ type SearchNormalized struct {
 	Metadata map[string]any       `json:"metadata"`
 }
 
+type AggregateGrouping struct {
+	Field string `json:"field"`
+	Limit int    `json:"limit,omitempty"`
+}
+
+type AggregateMetric struct {
+	Function      string         `json:"function"`
+	Field         string         `json:"field,omitempty"`
+	Configuration map[string]any `json:"configuration,omitempty"`
+}
+
+type AggregateRequest struct {
+	Query     string              `json:"query"`
+	Streams   []string            `json:"streams,omitempty"`
+	Timerange SearchTimerange     `json:"timerange"`
+	GroupBy   []AggregateGrouping `json:"group_by,omitempty"`
+	Metrics   []AggregateMetric   `json:"metrics"`
+}
+
+type AggregateResponse struct {
+	Schema   []SearchSchemaColumn `json:"schema"`
+	DataRows [][]any              `json:"datarows"`
+	Metadata map[string]any       `json:"metadata"`
+}
+
+type QueryValidationRequest struct {
+	Query     string           `json:"query"`
+	Timerange *SearchTimerange `json:"timerange,omitempty"`
+	Streams   []string         `json:"streams,omitempty"`
+}
+
+type QueryValidationMessage struct {
+	ErrorType       string `json:"error_type"`
+	ErrorTitle      string `json:"error_title"`
+	ErrorMessage    string `json:"error_message"`
+	BeginLine       int    `json:"begin_line"`
+	BeginColumn     int    `json:"begin_column"`
+	EndLine         int    `json:"end_line"`
+	EndColumn       int    `json:"end_column"`
+	RelatedProperty string `json:"related_property,omitempty"`
+}
+
+type QueryValidationResponse struct {
+	Status       string                   `json:"status"`
+	Explanations []QueryValidationMessage `json:"explanations"`
+}
+
+func (r QueryValidationResponse) Invalid() bool {
+	return strings.EqualFold(r.Status, "ERROR")
+}
+
+type MessageResponse struct {
+	Message map[string]any `json:"message"`
+	Index   string         `json:"index"`
+}
+
+type IndexRange struct {
+	IndexName string `json:"index_name"`
+	Begin     string `json:"begin"`
+	End       string `json:"end"`
+}
+
+type IndexRangesResponse struct {
+	Ranges []IndexRange `json:"ranges"`
+	Total  int          `json:"total"`
+}
+
+type FieldTypeInfo struct {
+	Type       string   `json:"type"`
+	Properties []string `json:"properties,omitempty"`
+}
+
+type FieldType struct {
+	Name    string        `json:"name"`
+	Type    FieldTypeInfo `json:"type"`
+	Streams []string      `json:"streams,omitempty"`
+}
+
+// FilterFields keeps fields seen in any of the given streams (fields without
+// stream information are always kept) whose name contains substr.
+func FilterFields(fields []FieldType, streams []string, substr string) []FieldType {
+	substr = strings.ToLower(substr)
+	out := make([]FieldType, 0, len(fields))
+	for _, f := range fields {
+		if substr != "" && !strings.Contains(strings.ToLower(f.Name), substr) {
+			continue
+		}
+		if len(streams) > 0 && len(f.Streams) > 0 && !sharesString(f.Streams, streams) {
+			continue
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+func sharesString(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type ExportQueryString struct {
+	Type        string `json:"type"`
+	QueryString string `json:"query_string"`
+}
+
+type ExportSort struct {
+	Field string `json:"field"`
+	Order string `json:"order"`
+}
+
+type ExportRequest struct {
+	QueryString   ExportQueryString `json:"query_string"`
+	Timerange     SearchTimerange   `json:"timerange"`
+	Streams       []string          `json:"streams,omitempty"`
+	FieldsInOrder []string          `json:"fields_in_order"`
+	Sort          []ExportSort      `json:"sort,omitempty"`
+	ChunkSize     int               `json:"chunk_size,omitempty"`
+	Limit         int               `json:"limit,omitempty"`
+}
+
+func NewExportRequest(req SearchMessagesRequest) ExportRequest {
+	out := ExportRequest{
+		QueryString:   ExportQueryString{Type: "elasticsearch", QueryString: req.Query},
+		Timerange:     req.Timerange,
+		Streams:       req.Streams,
+		FieldsInOrder: req.Fields,
+	}
+	if req.Sort != "" {
+		out.Sort = []ExportSort{{Field: req.Sort, Order: strings.ToUpper(req.SortOrder)}}
+	}
+	return out
+}
+
+var aggregateFunctions = map[string]bool{
+	"avg": true, "card": true, "count": true, "latest": true, "max": true, "min": true,
+	"percentile": true, "stddev": true, "sum": true, "sumofsquares": true, "variance": true,
+}
+
+func ParseAggregateGrouping(spec string) (AggregateGrouping, error) {
+	field, rawLimit, hasLimit := strings.Cut(strings.TrimSpace(spec), ":")
+	field = strings.TrimSpace(field)
+	if field == "" {
+		return AggregateGrouping{}, fmt.Errorf("invalid group-by %q: field is required", spec)
+	}
+	g := AggregateGrouping{Field: field}
+	if hasLimit {
+		limit, err := strconv.Atoi(strings.TrimSpace(rawLimit))
+		if err != nil || limit <= 0 {
+			return AggregateGrouping{}, fmt.Errorf("invalid group-by %q: limit must be a positive integer", spec)
+		}
+		g.Limit = limit
+	}
+	return g, nil
+}
+
+func ParseAggregateMetric(spec string) (AggregateMetric, error) {
+	parts := strings.Split(strings.TrimSpace(spec), ":")
+	fn := strings.ToLower(strings.TrimSpace(parts[0]))
+	if !aggregateFunctions[fn] {
+		return AggregateMetric{}, fmt.Errorf("invalid metric %q: unknown function %q", spec, fn)
+	}
+	m := AggregateMetric{Function: fn}
+	if len(parts) > 1 {
+		m.Field = strings.TrimSpace(parts[1])
+	}
+	if m.Field == "" && fn != "count" {
+		return AggregateMetric{}, fmt.Errorf("invalid metric %q: %s requires a field (%s:field)", spec, fn, fn)
+	}
+	switch {
+	case fn == "percentile":
+		if len(parts) != 3 {
+			return AggregateMetric{}, fmt.Errorf("invalid metric %q: use percentile:field:N", spec)
+		}
+		p, err := strconv.ParseFloat(strings.TrimSpace(parts[2]), 64)
+		if err != nil || p <= 0 || p > 100 {
+			return AggregateMetric{}, fmt.Errorf("invalid metric %q: percentile must be in (0, 100]", spec)
+		}
+		m.Configuration = map[string]any{"percentile": p}
+	case len(parts) > 2:
+		return AggregateMetric{}, fmt.Errorf("invalid metric %q: use function or function:field", spec)
+	}
+	return m, nil
+}
+
 func NormalizeSearchResponse(resp SearchMessagesResponse) SearchNormalized {
-	headers := make([]string, 0, len(resp.Schema))
-	for i, col := range resp.Schema {
+	return normalizeRows(resp.Schema, resp.DataRows, resp.Metadata)
+}
+
+func NormalizeAggregateResponse(resp AggregateResponse) SearchNormalized {
+	return normalizeRows(resp.Schema, resp.DataRows, resp.Metadata)
+}
+
+func normalizeRows(schema []SearchSchemaColumn, dataRows [][]any, metadata map[string]any) SearchNormalized {
+	headers := make([]string, 0, len(schema))
+	for i, col := range schema {
 		name := col.Name
 		if name == "" {
 			name = col.Field
@@ -65,8 +269,8 @@ func NormalizeSearchResponse(resp SearchMessagesResponse) SearchNormalized {
 		headers = append(headers, name)
 	}
 
-	rows := make([]map[string]any, 0, len(resp.DataRows))
-	for _, raw := range resp.DataRows {
+	rows := make([]map[string]any, 0, len(dataRows))
+	for _, raw := range dataRows {
 		row := make(map[string]any, len(headers))
 		for i, h := range headers {
 			if i < len(raw) {
@@ -78,5 +282,5 @@ func NormalizeSearchResponse(resp SearchMessagesResponse) SearchNormalized {
 		rows = append(rows, row)
 	}
 
-	return SearchNormalized{Schema: resp.Schema, Rows: rows, Metadata: resp.Metadata}
+	return SearchNormalized{Schema: schema, Rows: rows, Metadata: metadata}
 }
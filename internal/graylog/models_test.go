@@ -1,6 +1,9 @@
 package graylog
 
-import "testing"
+import (
+	"fmt"
+	"testing"
+)
 
 func TestNormalizeSearchResponse(t *testing.T) {
 	t.Parallel()
@@ -26,3 +29,75 @@ func TestNormalizeSearchResponse(t *testing.T) {
 		t.Fatalf("unexpected source value: %v", n.Rows[0]["source"])
 	}
 }
+
+func TestParseAggregateMetric(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		spec    string
+		want    AggregateMetric
+		wantErr bool
+	}{
+		{spec: "count", want: AggregateMetric{Function: "count"}},
+		{spec: "avg:took_ms", want: AggregateMetric{Function: "avg", Field: "took_ms"}},
+		{spec: "MAX:bytes", want: AggregateMetric{Function: "max", Field: "bytes"}},
+		{spec: "percentile:took_ms:95", want: AggregateMetric{Function: "percentile", Field: "took_ms", Configuration: map[string]any{"percentile": 95.0}}},
+		{spec: "avg", wantErr: true},
+		{spec: "median:took_ms", wantErr: true},
+		{spec: "percentile:took_ms", wantErr: true},
+		{spec: "percentile:took_ms:101", wantErr: true},
+		{spec: "max:bytes:1", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		got, err := ParseAggregateMetric(tc.spec)
+		if tc.wantErr {
+			if err == nil {
+				t.Fatalf("%s: expected error, got %+v", tc.spec, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.spec, err)
+		}
+		if got.Function != tc.want.Function || got.Field != tc.want.Field || fmt.Sprint(got.Configuration) != fmt.Sprint(tc.want.Configuration) {
+			t.Fatalf("%s: got %+v want %+v", tc.spec, got, tc.want)
+		}
+	}
+}
+
+func TestParseAggregateGrouping(t *testing.T) {
+	t.Parallel()
+
+	g, err := ParseAggregateGrouping("source:10")
+	if err != nil || g.Field != "source" || g.Limit != 10 {
+		t.Fatalf("unexpected grouping %+v (err %v)", g, err)
+	}
+	g, err = ParseAggregateGrouping("service")
+	if err != nil || g.Field != "service" || g.Limit != 0 {
+		t.Fatalf("unexpected grouping %+v (err %v)", g, err)
+	}
+	for _, spec := range []string{"", ":5", "source:0", "source:x"} {
+		if _, err := ParseAggregateGrouping(spec); err == nil {
+			t.Fatalf("%q: expected error", spec)
+		}
+	}
+}
+
+func TestFilterFields(t *testing.T) {
+	t.Parallel()
+
+	fields := []FieldType{
+		{Name: "http_status", Streams: []string{"s1"}},
+		{Name: "http_method", Streams: []string{"s2"}},
+		{Name: "source"},
+	}
+	got := FilterFields(fields, []string{"s1"}, "")
+	if len(got) != 2 || got[0].Name != "http_status" || got[1].Name != "source" {
+		t.Fatalf("unexpected stream filter result: %+v", got)
+	}
+	got = FilterFields(fields, nil, "HTTP")
+	if len(got) != 2 || got[1].Name != "http_method" {
+		t.Fatalf("unexpected substring filter result: %+v", got)
+	}
+}
@@ -0,0 +1,232 @@
+package graylog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	objectIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{24}$`)
+	uuidPattern     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+type StreamSummary struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+type InputSummary struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+type NodeSummary struct {
+	NodeID   string `json:"node_id"`
+	Hostname string `json:"hostname"`
+}
+
+type resourceKind struct {
+	name    string
+	apiPath string
+	idLike  *regexp.Regexp
+	load    func(ctx context.Context, c *Client) (map[string]string, error)
+}
+
+var (
+	streamsKind = resourceKind{name: "stream", apiPath: "/streams", idLike: objectIDPattern, load: func(ctx context.Context, c *Client) (map[string]string, error) {
+		var resp struct {
+			Streams []StreamSummary `json:"streams"`
+		}
+		if err := c.Do(ctx, http.MethodGet, "/streams", nil, &resp); err != nil {
+			return nil, err
+		}
+		names := make(map[string]string, len(resp.Streams))
+		for _, s := range resp.Streams {
+			names[s.ID] = s.Title
+		}
+		return names, nil
+	}}
+	inputsKind = resourceKind{name: "input", apiPath: "/system/inputs", idLike: objectIDPattern, load: func(ctx context.Context, c *Client) (map[string]string, error) {
+		var resp struct {
+			Inputs []InputSummary `json:"inputs"`
+		}
+		if err := c.Do(ctx, http.MethodGet, "/system/inputs", nil, &resp); err != nil {
+			return nil, err
+		}
+		names := make(map[string]string, len(resp.Inputs))
+		for _, in := range resp.Inputs {
+			names[in.ID] = in.Title
+		}
+		return names, nil
+	}}
+	nodesKind = resourceKind{name: "node", apiPath: "/cluster/nodes", idLike: uuidPattern, load: func(ctx context.Context, c *Client) (map[string]string, error) {
+		var resp struct {
+			Nodes []NodeSummary `json:"nodes"`
+		}
+		if err := c.Do(ctx, http.MethodGet, "/cluster/nodes", nil, &resp); err != nil {
+			return nil, err
+		}
+		names := make(map[string]string, len(resp.Nodes))
+		for _, n := range resp.Nodes {
+			names[n.NodeID] = n.Hostname
+		}
+		return names, nil
+	}}
+)
+
+// Resolver maps stream titles to ids, and stream, input and node ids back to
+// names. Each resource list is fetched at most once per Resolver.
+type Resolver struct {
+	c     *Client
+	mu    sync.Mutex
+	names map[string]map[string]string
+	// Warn receives a warning when EnrichSearchResponse may not read a
+	// resource list and leaves its ids unresolved.
+	Warn io.Writer
+}
+
+func NewResolver(c *Client) *Resolver {
+	return &Resolver{c: c, names: map[string]map[string]string{}}
+}
+
+func (r *Resolver) StreamIDs(ctx context.Context, refs []string) ([]string, error) {
+	if len(refs) == 0 {
+		return refs, nil
+	}
+	out := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		id, err := r.resolve(ctx, streamsKind, ref)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, nil
+}
+
+// EnrichSearchResponse replaces ids in the gl2_source_input, gl2_source_node
+// and streams columns with their names. Unknown ids are left untouched, as
+// are all ids of a resource the token is not allowed to list (403).
+func (r *Resolver) EnrichSearchResponse(ctx context.Context, resp *SearchMessagesResponse) error {
+	for i, col := range resp.Schema {
+		var kind resourceKind
+		switch columnKey(col, i) {
+		case "gl2_source_input":
+			kind = inputsKind
+		case "gl2_source_node":
+			kind = nodesKind
+		case "streams":
+			kind = streamsKind
+		default:
+			continue
+		}
+		names, err := r.load(ctx, kind)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden {
+			names = r.skip(kind, err)
+		} else if err != nil {
+			return err
+		}
+		for _, row := range resp.DataRows {
+			if i < len(row) {
+				row[i] = renameIDs(row[i], names)
+			}
+		}
+	}
+	return nil
+}
+
+func renameIDs(v any, names map[string]string) any {
+	switch val := v.(type) {
+	case string:
+		if name, ok := names[val]; ok && name != "" {
+			return name
+		}
+		return val
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = renameIDs(item, names)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func (r *Resolver) resolve(ctx context.Context, kind resourceKind, ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return "", fmt.Errorf("empty %s reference", kind.name)
+	}
+	if kind.idLike.MatchString(ref) {
+		return ref, nil
+	}
+	names, err := r.load(ctx, kind)
+	if err != nil {
+		return "", fmt.Errorf("resolve %s %q: %w", kind.name, ref, err)
+	}
+	if _, ok := names[ref]; ok {
+		return ref, nil
+	}
+
+	var exact, folded []string
+	for id, name := range names {
+		switch {
+		case name == ref:
+			exact = append(exact, id)
+		case strings.EqualFold(name, ref):
+			folded = append(folded, id)
+		}
+	}
+	matches := exact
+	if len(matches) == 0 {
+		matches = folded
+	}
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		return "", fmt.Errorf("no %s named %q (GET %s)", kind.name, ref, kind.apiPath)
+	default:
+		sort.Strings(matches)
+		return "", fmt.Errorf("%s name %q is ambiguous; use one of the ids %s", kind.name, ref, strings.Join(matches, ", "))
+	}
+}
+
+// skip caches an empty name list for kind so it is neither requested nor
+// reported again.
+func (r *Resolver) skip(kind resourceKind, err error) map[string]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if names, ok := r.names[kind.name]; ok {
+		return names
+	}
+	if r.Warn != nil {
+		fmt.Fprintf(r.Warn, "warning: cannot list %ss (%v); leaving %s ids unresolved\n", kind.name, err, kind.name)
+	}
+	names := map[string]string{}
+	r.names[kind.name] = names
+	return names
+}
+
+func (r *Resolver) load(ctx context.Context, kind resourceKind) (map[string]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if names, ok := r.names[kind.name]; ok {
+		return names, nil
+	}
+	names, err := kind.load(ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+	r.names[kind.name] = names
+	return names, nil
+}
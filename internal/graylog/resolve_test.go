@@ -0,0 +1,138 @@
+package graylog
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newResolverTestServer(t *testing.T, calls map[string]int) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls[r.URL.Path]++
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/streams":
+			_, _ = w.Write([]byte(`{"total":3,"streams":[
+				{"id":"6900fa30becaa4ac09796c05","title":"nginx-prod"},
+				{"id":"6900fa30becaa4ac09796c06","title":"dup"},
+				{"id":"6900fa30becaa4ac09796c07","title":"dup"}]}`))
+		case "/api/system/inputs":
+			_, _ = w.Write([]byte(`{"total":1,"inputs":[{"id":"5f00aa30becaa4ac09796c01","title":"GELF UDP"}]}`))
+		case "/api/cluster/nodes":
+			_, _ = w.Write([]byte(`{"total":1,"nodes":[{"node_id":"3c2b1a00-1111-2222-3333-444455556666","hostname":"graylog-1"}]}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+}
+
+func TestResolverStreamIDs(t *testing.T) {
+	t.Parallel()
+
+	calls := map[string]int{}
+	srv := newResolverTestServer(t, calls)
+	defer srv.Close()
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL, APIBase: "/api"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	r := NewResolver(c)
+
+	ids, err := r.StreamIDs(context.Background(), []string{"6900fa30becaa4ac09796c99"})
+	if err != nil || ids[0] != "6900fa30becaa4ac09796c99" || calls["/api/streams"] != 0 {
+		t.Fatalf("expected id passthrough without lookup, got %v (err %v, calls %v)", ids, err, calls)
+	}
+	ids, err = r.StreamIDs(context.Background(), []string{"nginx-prod", "NGINX-PROD"})
+	if err != nil || ids[0] != "6900fa30becaa4ac09796c05" || ids[1] != "6900fa30becaa4ac09796c05" {
+		t.Fatalf("unexpected ids %v (err %v)", ids, err)
+	}
+	if calls["/api/streams"] != 1 {
+		t.Fatalf("expected streams to be fetched once, got %d", calls["/api/streams"])
+	}
+	if _, err := r.StreamIDs(context.Background(), []string{"dup"}); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Fatalf("expected ambiguity error, got %v", err)
+	}
+	if _, err := r.StreamIDs(context.Background(), []string{"missing"}); err == nil || !strings.Contains(err.Error(), `no stream named "missing"`) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestResolverEnrichSearchResponse(t *testing.T) {
+	t.Parallel()
+
+	srv := newResolverTestServer(t, map[string]int{})
+	defer srv.Close()
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL, APIBase: "/api"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	resp := SearchMessagesResponse{
+		Schema: []SearchSchemaColumn{{Name: "gl2_source_input"}, {Name: "gl2_source_node"}, {Name: "streams"}, {Name: "message"}},
+		DataRows: [][]any{{
+			"5f00aa30becaa4ac09796c01",
+			"3c2b1a00-1111-2222-3333-444455556666",
+			[]any{"6900fa30becaa4ac09796c05", "000000000000000000000001"},
+			"5f00aa30becaa4ac09796c01",
+		}},
+	}
+	if err := NewResolver(c).EnrichSearchResponse(context.Background(), &resp); err != nil {
+		t.Fatalf("enrich: %v", err)
+	}
+	row := resp.DataRows[0]
+	if row[0] != "GELF UDP" || row[1] != "graylog-1" || row[3] != "5f00aa30becaa4ac09796c01" {
+		t.Fatalf("unexpected enriched row: %v", row)
+	}
+	streams := row[2].([]any)
+	if streams[0] != "nginx-prod" || streams[1] != "000000000000000000000001" {
+		t.Fatalf("unexpected streams: %v", streams)
+	}
+}
+
+func TestResolverEnrichSkipsForbiddenResources(t *testing.T) {
+	t.Parallel()
+
+	calls := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls[r.URL.Path]++
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/streams":
+			_, _ = w.Write([]byte(`{"streams":[{"id":"6900fa30becaa4ac09796c05","title":"nginx-prod"}]}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"type":"ApiError","message":"Not authorized"}`))
+		}
+	}))
+	defer srv.Close()
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL, APIBase: "/api"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	var warn strings.Builder
+	r := NewResolver(c)
+	r.Warn = &warn
+
+	for i := 0; i < 2; i++ {
+		resp := SearchMessagesResponse{
+			Schema:   []SearchSchemaColumn{{Name: "gl2_source_input"}, {Name: "gl2_source_node"}, {Name: "streams"}},
+			DataRows: [][]any{{"5f00aa30becaa4ac09796c01", "3c2b1a00-1111-2222-3333-444455556666", []any{"6900fa30becaa4ac09796c05"}}},
+		}
+		if err := r.EnrichSearchResponse(context.Background(), &resp); err != nil {
+			t.Fatalf("enrich: %v", err)
+		}
+		row := resp.DataRows[0]
+		if row[0] != "5f00aa30becaa4ac09796c01" || row[1] != "3c2b1a00-1111-2222-3333-444455556666" || row[2].([]any)[0] != "nginx-prod" {
+			t.Fatalf("unexpected enriched row: %v", row)
+		}
+	}
+	if calls["/api/system/inputs"] != 1 || calls["/api/cluster/nodes"] != 1 {
+		t.Fatalf("expected forbidden lists to be requested once, got %v", calls)
+	}
+	if strings.Count(warn.String(), "warning:") != 2 || !strings.Contains(warn.String(), "input ids unresolved") {
+		t.Fatalf("expected one warning per forbidden list, got:\n%s", warn.String())
+	}
+}
@@ -0,0 +1,169 @@
+package graylog
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultRetryBaseDelay = 500 * time.Millisecond
+	DefaultRetryMaxDelay  = 10 * time.Second
+	// maxRetryAfter caps how long a server-provided Retry-After is honored.
+	maxRetryAfter = time.Minute
+)
+
+// RetryPolicy retries transport errors and 429/502/503/504 responses with
+// exponential backoff and jitter. Only idempotent requests are retried unless
+// RetryAllMethods is set; POSTs to the read-only search endpoints count as
+// idempotent.
+type RetryPolicy struct {
+	MaxRetries      int
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	RetryAllMethods bool
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = DefaultRetryBaseDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = DefaultRetryMaxDelay
+	}
+	return p
+}
+
+func (p RetryPolicy) allows(method, apiPath string) bool {
+	if p.MaxRetries <= 0 {
+		return false
+	}
+	return p.RetryAllMethods || isIdempotent(method, apiPath)
+}
+
+// backoff returns the delay before retry n (0-based): exponential growth
+// capped at MaxDelay, with the upper half randomized.
+func (p RetryPolicy) backoff(n int) time.Duration {
+	d := float64(p.BaseDelay) * math.Pow(2, float64(n))
+	if d > float64(p.MaxDelay) {
+		d = float64(p.MaxDelay)
+	}
+	half := time.Duration(d / 2)
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+func isIdempotent(method, apiPath string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPost:
+		return isReadOnlySearchPath(apiPath)
+	}
+	return false
+}
+
+// isReadOnlySearchPath matches the search POSTs that change nothing.
+func isReadOnlySearchPath(apiPath string) bool {
+	switch normalizeAPIPath(apiPath) {
+	case "/search/messages", "/search/aggregate", "/search/validate", "/views/search/messages":
+		return true
+	}
+	return false
+}
+
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func retryableError(ctx context.Context, err error) bool {
+	return ctx.Err() == nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func retryAfter(h http.Header, now time.Time) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	var d time.Duration
+	if secs, err := strconv.Atoi(v); err == nil {
+		d = time.Duration(secs) * time.Second
+	} else if t, err := http.ParseTime(v); err == nil {
+		d = t.Sub(now)
+	} else {
+		return 0, false
+	}
+	if d < 0 {
+		d = 0
+	}
+	if d > maxRetryAfter {
+		d = maxRetryAfter
+	}
+	return d, true
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// rateLimiter is a token bucket allowing rate requests per second with a burst
+// of at least one request.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+func newRateLimiter(rate float64) *rateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	burst := math.Max(1, math.Ceil(rate))
+	return &rateLimiter{rate: rate, burst: burst, tokens: burst, now: time.Now}
+}
+
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	for {
+		l.mu.Lock()
+		now := l.now()
+		if !l.last.IsZero() {
+			l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+		}
+		l.last = now
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
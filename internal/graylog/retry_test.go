@@ -0,0 +1,160 @@
+package graylog
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDoRetriesTransientStatus(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		if n < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"schema":[],"datarows":[]}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL, Token: "t", Retry: RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond}})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	if _, err := c.SearchMessages(context.Background(), SearchMessagesRequest{Query: "*"}); err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestDoGivesUpAfterMaxRetries(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL, Token: "t", Retry: RetryPolicy{MaxRetries: 1, BaseDelay: time.Millisecond}})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	err = c.Do(context.Background(), http.MethodGet, "/system", nil, nil)
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected 502 APIError, got %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 attempts, got %d", calls)
+	}
+}
+
+func TestDoDoesNotRetryNonIdempotentPost(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL, Retry: RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond}})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	_ = c.Do(context.Background(), http.MethodPost, "/system/sessions", SessionRequest{Username: "u"}, nil)
+	if calls != 1 {
+		t.Fatalf("expected a single attempt, got %d", calls)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 2, 18, 10, 0, 0, 0, time.UTC)
+	h := http.Header{}
+	if _, ok := retryAfter(h, now); ok {
+		t.Fatalf("expected no Retry-After")
+	}
+	h.Set("Retry-After", "7")
+	if d, ok := retryAfter(h, now); !ok || d != 7*time.Second {
+		t.Fatalf("unexpected delay %s", d)
+	}
+	h.Set("Retry-After", now.Add(30*time.Second).Format(http.TimeFormat))
+	if d, ok := retryAfter(h, now); !ok || d != 30*time.Second {
+		t.Fatalf("unexpected delay %s", d)
+	}
+	h.Set("Retry-After", "3600")
+	if d, _ := retryAfter(h, now); d != maxRetryAfter {
+		t.Fatalf("expected cap, got %s", d)
+	}
+}
+
+func TestBackoffBounds(t *testing.T) {
+	t.Parallel()
+
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for n, max := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second} {
+		for i := 0; i < 20; i++ {
+			d := p.backoff(n)
+			if d < max/2 || d > max {
+				t.Fatalf("backoff(%d) = %s, want within [%s, %s]", n, d, max/2, max)
+			}
+		}
+	}
+}
+
+func TestRateLimiterSpacesRequests(t *testing.T) {
+	t.Parallel()
+
+	l := newRateLimiter(2)
+	now := time.Date(2026, 2, 18, 10, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+
+	// The burst of two is available immediately.
+	for i := 0; i < 2; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("wait: %v", err)
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.Wait(ctx); err == nil {
+		t.Fatalf("expected an empty bucket to block")
+	}
+	now = now.Add(500 * time.Millisecond)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("expected a token after 500ms: %v", err)
+	}
+	if newRateLimiter(0) != nil {
+		t.Fatalf("expected rate 0 to disable limiting")
+	}
+}
+
+func TestIsIdempotent(t *testing.T) {
+	t.Parallel()
+
+	for _, p := range []string{"/search/messages", "/search/aggregate", "/search/validate", "/views/search/messages", "search/messages", "/views/search/messages/"} {
+		if !isIdempotent(http.MethodPost, p) {
+			t.Errorf("expected POST %s to be retried", p)
+		}
+	}
+	for _, p := range []string{"/streams", "/system/sessions", "/views/search", "views/search/"} {
+		if isIdempotent(http.MethodPost, p) {
+			t.Errorf("expected POST %s not to be retried", p)
+		}
+	}
+}
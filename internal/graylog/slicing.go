@@ -0,0 +1,261 @@
+package graylog
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const DefaultResultWindow = 10000
+
+type SliceOptions struct {
+	Window      int
+	PageSize    int
+	Concurrency int
+	// Offset and Limit select rows of the merged result like from/size of a
+	// single search; Limit 0 returns every row after Offset.
+	Offset int
+	Limit  int
+}
+
+type timeSlice struct {
+	from, to time.Time
+	count    int
+}
+
+// sliceJob fetches up to max rows of a slice starting at its row from.
+type sliceJob struct {
+	slice     timeSlice
+	from, max int
+}
+
+// SearchMessagesSliced runs an absolute search of any depth, splitting the
+// timerange in halves until each slice holds no more than opts.Window
+// messages, so no request needs from+size beyond the index result window.
+// Slices are fetched concurrently and merged in the requested sort order.
+func (c *Client) SearchMessagesSliced(ctx context.Context, req SearchMessagesRequest, opts SliceOptions) (SearchMessagesResponse, error) {
+	if req.Timerange.Type != "absolute" {
+		return SearchMessagesResponse{}, fmt.Errorf("time slicing needs an absolute timerange, got %q", req.Timerange.Type)
+	}
+	if opts.Offset < 0 || opts.Limit < 0 {
+		return SearchMessagesResponse{}, fmt.Errorf("offset and limit must be >= 0")
+	}
+	if opts.Window <= 0 {
+		opts.Window = DefaultResultWindow
+	}
+	if opts.PageSize <= 0 || opts.PageSize > opts.Window {
+		opts.PageSize = min(1000, opts.Window)
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+	from, to, err := req.Timerange.Bounds(time.Now())
+	if err != nil {
+		return SearchMessagesResponse{}, err
+	}
+
+	slices, err := c.planSlices(ctx, req, timeSlice{from: from, to: to}, opts.Window)
+	if err != nil {
+		return SearchMessagesResponse{}, err
+	}
+	if req.Sort == "" {
+		req.Sort = "timestamp"
+	}
+	desc := req.SortOrder != "asc"
+	if desc {
+		for i, j := 0, len(slices)-1; i < j; i, j = i+1, j-1 {
+			slices[i], slices[j] = slices[j], slices[i]
+		}
+	}
+	byTime := req.Sort == "timestamp"
+	jobs := planSliceJobs(slices, opts, byTime)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	results := make([]SearchMessagesResponse, len(jobs))
+	sem := make(chan struct{}, opts.Concurrency)
+	for i, j := range jobs {
+		i, j := i, j
+		sem <- struct{}{}
+		if ctx.Err() != nil {
+			<-sem
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			sliceReq := req
+			sliceReq.From = j.from
+			sliceReq.Size = opts.PageSize
+			sliceReq.Timerange = AbsoluteTimerange(j.slice.from, j.slice.to)
+			_, err := c.searchPages(ctx, sliceReq, j.max, opts.Window, func(page SearchMessagesResponse) error {
+				if results[i].Schema == nil {
+					results[i].Schema = page.Schema
+				}
+				results[i].DataRows = append(results[i].DataRows, page.DataRows...)
+				return nil
+			})
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("slice %s: %w", sliceReq.Timerange.From, err)
+				}
+				mu.Unlock()
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return SearchMessagesResponse{}, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return SearchMessagesResponse{}, err
+	}
+
+	merged := SearchMessagesResponse{Metadata: map[string]any{
+		"effective_timerange": req.Timerange,
+		"slices":              len(slices),
+	}}
+	for _, r := range results {
+		if merged.Schema == nil {
+			merged.Schema = r.Schema
+		}
+		merged.DataRows = append(merged.DataRows, r.DataRows...)
+	}
+	if !byTime {
+		SortDataRows(merged.Schema, merged.DataRows, req.Sort, desc)
+		merged.DataRows = pageRows(merged.DataRows, opts.Offset, opts.Limit)
+	} else if opts.Limit > 0 && len(merged.DataRows) > opts.Limit {
+		merged.DataRows = merged.DataRows[:opts.Limit]
+	}
+	return merged, nil
+}
+
+// planSliceJobs decides what to fetch from each slice. Sorted by timestamp,
+// slices are already in result order, so their counts locate the offset and
+// only slices overlapping offset..offset+limit are fetched. Otherwise every
+// slice is fetched and the page is cut after sorting. Fetches never go past a
+// slice's count, which keeps from+size within the window.
+func planSliceJobs(slices []timeSlice, opts SliceOptions, byTime bool) []sliceJob {
+	jobs := make([]sliceJob, 0, len(slices))
+	if !byTime {
+		for _, s := range slices {
+			jobs = append(jobs, sliceJob{slice: s, max: s.count})
+		}
+		return jobs
+	}
+	start := 0
+	for _, s := range slices {
+		end := start + s.count
+		if opts.Limit > 0 && start >= opts.Offset+opts.Limit {
+			break
+		}
+		if end > opts.Offset {
+			j := sliceJob{slice: s, from: max(0, opts.Offset-start)}
+			j.max = s.count - j.from
+			if opts.Limit > 0 {
+				j.max = min(j.max, opts.Offset+opts.Limit-(start+j.from))
+			}
+			jobs = append(jobs, j)
+		}
+		start = end
+	}
+	return jobs
+}
+
+func pageRows(rows [][]any, offset, limit int) [][]any {
+	if offset >= len(rows) {
+		return nil
+	}
+	rows = rows[offset:]
+	if limit > 0 && len(rows) > limit {
+		rows = rows[:limit]
+	}
+	return rows
+}
+
+func (c *Client) planSlices(ctx context.Context, req SearchMessagesRequest, s timeSlice, window int) ([]timeSlice, error) {
+	n, err := c.CountMessages(ctx, req.Query, req.Streams, AbsoluteTimerange(s.from, s.to))
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, nil
+	}
+	if n <= window {
+		s.count = n
+		return []timeSlice{s}, nil
+	}
+	span := s.to.Sub(s.from)
+	if span < 2*time.Millisecond {
+		return nil, fmt.Errorf("%d messages at %s exceed the result window of %d and cannot be split further", n, AbsoluteTimerange(s.from, s.to).From, window)
+	}
+	mid := s.from.Add(span / 2).Truncate(time.Millisecond)
+	left, err := c.planSlices(ctx, req, timeSlice{from: s.from, to: mid}, window)
+	if err != nil {
+		return nil, err
+	}
+	right, err := c.planSlices(ctx, req, timeSlice{from: mid.Add(time.Millisecond), to: s.to}, window)
+	if err != nil {
+		return nil, err
+	}
+	return append(left, right...), nil
+}
+
+// SortDataRows stably sorts rows by the column named field. Timestamps and
+// numbers compare by value, everything else as text.
+func SortDataRows(schema []SearchSchemaColumn, rows [][]any, field string, desc bool) {
+	idx := -1
+	for i, col := range schema {
+		if col.Name == field || col.Field == field {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return
+	}
+	value := func(row []any) any {
+		if idx < len(row) {
+			return row[idx]
+		}
+		return nil
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		cmp := compareValues(value(rows[i]), value(rows[j]))
+		if desc {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+}
+
+func compareValues(a, b any) int {
+	if ta, ok := ParseTimestamp(a); ok {
+		if tb, ok := ParseTimestamp(b); ok {
+			return ta.Compare(tb)
+		}
+	}
+	if fa, ok := a.(float64); ok {
+		if fb, ok := b.(float64); ok {
+			switch {
+			case fa < fb:
+				return -1
+			case fa > fb:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
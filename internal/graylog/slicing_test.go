@@ -0,0 +1,211 @@
+package graylog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newSliceServer serves counts and descending timestamp pages over messages,
+// failing any page request beyond window. Message requests are counted in
+// searches.
+func newSliceServer(t *testing.T, messages []time.Time, window int, searches *atomic.Int64) *httptest.Server {
+	inRange := func(tr SearchTimerange) []time.Time {
+		from, to, err := tr.Bounds(time.Now())
+		if err != nil {
+			t.Errorf("bounds: %v", err)
+		}
+		var out []time.Time
+		for _, m := range messages {
+			if !m.Before(from) && !m.After(to) {
+				out = append(out, m)
+			}
+		}
+		return out
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/search/aggregate":
+			var req AggregateRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			_ = json.NewEncoder(w).Encode(map[string]any{"datarows": [][]any{{len(inRange(req.Timerange))}}})
+		case "/api/search/messages":
+			searches.Add(1)
+			var req SearchMessagesRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			if req.From+req.Size > window {
+				t.Errorf("from+size %d exceeds window", req.From+req.Size)
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			hits := inRange(req.Timerange)
+			sort.Slice(hits, func(i, j int) bool { return hits[i].After(hits[j]) })
+			rows := [][]any{}
+			for i := req.From; i < len(hits) && i < req.From+req.Size; i++ {
+				rows = append(rows, []any{hits[i].Format(TimestampLayout)})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"schema": []map[string]any{{"name": "timestamp"}}, "datarows": rows})
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+}
+
+func sliceTestMessages(base time.Time, n int) []time.Time {
+	var messages []time.Time
+	for i := 0; i < n; i++ {
+		messages = append(messages, base.Add(time.Duration(i)*time.Second))
+	}
+	return messages
+}
+
+func TestSearchMessagesSlicedStaysWithinWindow(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 2, 18, 10, 0, 0, 0, time.UTC)
+	messages := sliceTestMessages(base, 25)
+	const window = 10
+	var searches atomic.Int64
+	srv := newSliceServer(t, messages, window, &searches)
+	defer srv.Close()
+
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL, APIBase: "/api"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	req := SearchMessagesRequest{
+		Query:     "*",
+		Fields:    []string{"timestamp"},
+		SortOrder: "desc",
+		Timerange: AbsoluteTimerange(base, base.Add(time.Minute)),
+	}
+	resp, err := c.SearchMessagesSliced(context.Background(), req, SliceOptions{Window: window, Concurrency: 3})
+	if err != nil {
+		t.Fatalf("sliced search: %v", err)
+	}
+	if len(resp.DataRows) != len(messages) {
+		t.Fatalf("expected %d rows, got %d", len(messages), len(resp.DataRows))
+	}
+	for i, row := range resp.DataRows {
+		want := messages[len(messages)-1-i].Format(TimestampLayout)
+		if row[0] != want {
+			t.Fatalf("row %d: got %v want %s", i, row[0], want)
+		}
+	}
+	if resp.Metadata["slices"].(int) < 3 {
+		t.Fatalf("expected at least 3 slices, got %v", resp.Metadata["slices"])
+	}
+}
+
+func TestSearchMessagesSlicedOffsetAndLimit(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 2, 18, 10, 0, 0, 0, time.UTC)
+	messages := sliceTestMessages(base, 25)
+	const window = 10
+	var searches atomic.Int64
+	srv := newSliceServer(t, messages, window, &searches)
+	defer srv.Close()
+
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL, APIBase: "/api"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	req := SearchMessagesRequest{
+		Query:     "*",
+		Fields:    []string{"timestamp"},
+		SortOrder: "desc",
+		Timerange: AbsoluteTimerange(base, base.Add(time.Minute)),
+	}
+	// Offset 12 is past the window of any single request.
+	resp, err := c.SearchMessagesSliced(context.Background(), req, SliceOptions{Window: window, Offset: 12, Limit: 5})
+	if err != nil {
+		t.Fatalf("sliced search: %v", err)
+	}
+	if len(resp.DataRows) != 5 {
+		t.Fatalf("expected 5 rows, got %d", len(resp.DataRows))
+	}
+	for i, row := range resp.DataRows {
+		want := messages[len(messages)-1-12-i].Format(TimestampLayout)
+		if row[0] != want {
+			t.Fatalf("row %d: got %v want %s", i, row[0], want)
+		}
+	}
+	if n := searches.Load(); n > 2 {
+		t.Fatalf("expected only the overlapping slices to be fetched, got %d searches", n)
+	}
+
+	resp, err = c.SearchMessagesSliced(context.Background(), req, SliceOptions{Window: window, Offset: 30, Limit: 5})
+	if err != nil {
+		t.Fatalf("sliced search: %v", err)
+	}
+	if len(resp.DataRows) != 0 {
+		t.Fatalf("expected no rows past the end, got %d", len(resp.DataRows))
+	}
+
+	req.Sort = "source"
+	resp, err = c.SearchMessagesSliced(context.Background(), req, SliceOptions{Window: window, Offset: 20, Limit: 10})
+	if err != nil {
+		t.Fatalf("sliced search: %v", err)
+	}
+	if len(resp.DataRows) != 5 {
+		t.Fatalf("expected the last 5 rows for a non-timestamp sort, got %d", len(resp.DataRows))
+	}
+}
+
+func TestSearchMessagesSlicedStopsOnFirstError(t *testing.T) {
+	t.Parallel()
+
+	var searches atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/search/aggregate" {
+			var req AggregateRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			from, to, _ := req.Timerange.Bounds(time.Now())
+			// Ten messages per second: every one-second slice fits a window of 10.
+			_ = json.NewEncoder(w).Encode(map[string]any{"datarows": [][]any{{int(to.Sub(from).Seconds()*10) + 1}}})
+			return
+		}
+		searches.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"message":"boom"}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL, APIBase: "/api"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	base := time.Date(2026, 2, 18, 10, 0, 0, 0, time.UTC)
+	req := SearchMessagesRequest{Query: "*", Timerange: AbsoluteTimerange(base, base.Add(8*time.Second))}
+	_, err = c.SearchMessagesSliced(context.Background(), req, SliceOptions{Window: 10, Concurrency: 1})
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected slice error, got %v", err)
+	}
+	if n := searches.Load(); n != 1 {
+		t.Fatalf("expected remaining slices to be skipped, got %d searches", n)
+	}
+}
+
+func TestSortDataRows(t *testing.T) {
+	t.Parallel()
+
+	schema := []SearchSchemaColumn{{Name: "source"}, {Name: "took_ms"}}
+	rows := [][]any{{"b", 20.0}, {"a", 3.0}, {"c", 100.0}}
+	SortDataRows(schema, rows, "took_ms", false)
+	if rows[0][0] != "a" || rows[1][0] != "b" || rows[2][0] != "c" {
+		t.Fatalf("unexpected numeric order: %v", rows)
+	}
+	SortDataRows(schema, rows, "source", true)
+	if rows[0][0] != "c" || rows[2][0] != "a" {
+		t.Fatalf("unexpected text order: %v", rows)
+	}
+}
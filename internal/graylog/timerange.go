@@ -0,0 +1,72 @@
+package graylog
+
+import (
+	"fmt"
+	"time"
+)
+
+const TimestampLayout = "2006-01-02T15:04:05.000Z"
+
+func AbsoluteTimerange(from, to time.Time) SearchTimerange {
+	return SearchTimerange{
+		Type: "absolute",
+		From: from.UTC().Format(TimestampLayout),
+		To:   to.UTC().Format(TimestampLayout),
+	}
+}
+
+func ParseTimestamp(v any) (time.Time, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.000", "2006-01-02T15:04:05.000"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (t SearchTimerange) Bounds(now time.Time) (time.Time, time.Time, error) {
+	switch t.Type {
+	case "relative":
+		if t.Range <= 0 {
+			return time.Time{}, time.Time{}, fmt.Errorf("relative timerange must be > 0 seconds")
+		}
+		to := now.UTC()
+		return to.Add(-time.Duration(t.Range) * time.Second), to, nil
+	case "absolute":
+		from, ok := ParseTimestamp(t.From)
+		if !ok {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid absolute from timestamp %q", t.From)
+		}
+		to, ok := ParseTimestamp(t.To)
+		if !ok {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid absolute to timestamp %q", t.To)
+		}
+		if !to.After(from) {
+			return time.Time{}, time.Time{}, fmt.Errorf("absolute timerange end %s must be after start %s", t.To, t.From)
+		}
+		return from, to, nil
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("%s timerange cannot be resolved to absolute bounds", t.Type)
+	}
+}
+
+// SplitTimerange cuts [from, to] into interval-aligned buckets. Graylog treats
+// both ends of an absolute range as inclusive, so each bucket ends 1ms before
+// the next one starts.
+func SplitTimerange(from, to time.Time, interval time.Duration) [][2]time.Time {
+	var out [][2]time.Time
+	for start := from; start.Before(to); {
+		next := start.Truncate(interval).Add(interval)
+		end := next.Add(-time.Millisecond)
+		if !next.Before(to) {
+			end = to
+		}
+		out = append(out, [2]time.Time{start, end})
+		start = next
+	}
+	return out
+}
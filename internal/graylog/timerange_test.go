@@ -0,0 +1,55 @@
+package graylog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAbsoluteTimerange(t *testing.T) {
+	t.Parallel()
+
+	loc := time.FixedZone("CET", 3600)
+	tr := AbsoluteTimerange(time.Date(2026, 2, 18, 11, 0, 0, 0, loc), time.Date(2026, 2, 18, 11, 5, 0, 250e6, loc))
+	if tr.Type != "absolute" || tr.From != "2026-02-18T10:00:00.000Z" || tr.To != "2026-02-18T10:05:00.250Z" {
+		t.Fatalf("unexpected timerange: %+v", tr)
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	t.Parallel()
+
+	want := time.Date(2026, 2, 18, 10, 0, 0, 123e6, time.UTC)
+	for _, v := range []any{"2026-02-18T10:00:00.123Z", "2026-02-18 10:00:00.123", "2026-02-18T11:00:00.123+01:00"} {
+		got, ok := ParseTimestamp(v)
+		if !ok || !got.Equal(want) {
+			t.Fatalf("parse %v: got %v ok=%v", v, got, ok)
+		}
+	}
+	if _, ok := ParseTimestamp(42); ok {
+		t.Fatalf("expected non-string to fail")
+	}
+}
+
+func TestTimerangeBounds(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 2, 18, 10, 0, 0, 0, time.UTC)
+	from, to, err := SearchTimerange{Type: "relative", Range: 300}.Bounds(now)
+	if err != nil || !from.Equal(now.Add(-5*time.Minute)) || !to.Equal(now) {
+		t.Fatalf("relative bounds: %v %v %v", from, to, err)
+	}
+	from, to, err = SearchTimerange{Type: "absolute", From: "2026-02-18T09:00:00Z", To: "2026-02-18T10:00:00.000Z"}.Bounds(now)
+	if err != nil || !from.Equal(now.Add(-time.Hour)) || !to.Equal(now) {
+		t.Fatalf("absolute bounds: %v %v %v", from, to, err)
+	}
+	for _, tr := range []SearchTimerange{
+		{Type: "relative"},
+		{Type: "absolute", From: "bad", To: "2026-02-18T10:00:00Z"},
+		{Type: "absolute", From: "2026-02-18T10:00:00Z", To: "2026-02-18T09:00:00Z"},
+		{Type: "keyword", Keyword: "last hour"},
+	} {
+		if _, _, err := tr.Bounds(now); err == nil {
+			t.Fatalf("expected error for %+v", tr)
+		}
+	}
+}
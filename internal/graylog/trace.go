@@ -0,0 +1,242 @@
+package graylog
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type TraceLevel int
+
+const (
+	TraceOff TraceLevel = iota
+	// TraceVerbose logs one line per request and one per response with a
+	// timing breakdown.
+	TraceVerbose
+	// TraceFull also logs request and response headers and the request body.
+	TraceFull
+)
+
+// sensitiveHeaderParts marks headers whose values are never logged.
+var sensitiveHeaderParts = []string{"authorization", "cookie", "session", "token"}
+
+// sensitiveQueryParts marks query parameters whose values are never logged.
+var sensitiveQueryParts = []string{"session", "token", "password"}
+
+// redactedURLValue replaces secrets inside URLs, where "<redacted>" would be
+// percent-encoded.
+const redactedURLValue = "REDACTED"
+
+// tracer writes request logs. Lines of concurrent requests are tagged with a
+// sequence number so they can be told apart.
+type tracer struct {
+	level TraceLevel
+	mu    sync.Mutex
+	out   io.Writer
+	seq   atomic.Int64
+}
+
+func newTracer(level TraceLevel, out io.Writer) *tracer {
+	if level == TraceOff {
+		return nil
+	}
+	if out == nil {
+		out = os.Stderr
+	}
+	return &tracer{level: level, out: out}
+}
+
+func (t *tracer) printf(format string, args ...any) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fmt.Fprintf(t.out, format, args...)
+}
+
+// requestTrace collects httptrace timings for one attempt.
+type requestTrace struct {
+	t     *tracer
+	id    int64
+	start time.Time
+
+	mu                  sync.Mutex
+	dnsStart, dnsDone   time.Time
+	connStart, connDone time.Time
+	tlsStart, tlsDone   time.Time
+	firstByte           time.Time
+	reused              bool
+}
+
+// begin logs the request and returns it with an httptrace attached.
+func (t *tracer) begin(req *http.Request, body []byte, attempt int) (*http.Request, *requestTrace) {
+	rt := &requestTrace{t: t, id: t.seq.Add(1), start: time.Now()}
+	var b strings.Builder
+	fmt.Fprintf(&b, "> [%d] %s %s", rt.id, req.Method, redactURL(req.URL))
+	if attempt > 0 {
+		fmt.Fprintf(&b, " (retry %d)", attempt)
+	}
+	b.WriteByte('\n')
+	if t.level >= TraceFull {
+		writeTraceHeaders(&b, ">", rt.id, req.Header)
+		if len(body) > 0 {
+			fmt.Fprintf(&b, "> [%d] %s\n", rt.id, redactBody(body))
+		}
+	}
+	t.printf("%s", b.String())
+
+	ct := &httptrace.ClientTrace{
+		DNSStart:          func(httptrace.DNSStartInfo) { rt.mark(&rt.dnsStart) },
+		DNSDone:           func(httptrace.DNSDoneInfo) { rt.mark(&rt.dnsDone) },
+		ConnectStart:      func(string, string) { rt.markOnce(&rt.connStart) },
+		ConnectDone:       func(string, string, error) { rt.mark(&rt.connDone) },
+		TLSHandshakeStart: func() { rt.mark(&rt.tlsStart) },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { rt.mark(&rt.tlsDone) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			rt.mu.Lock()
+			rt.reused = info.Reused
+			rt.mu.Unlock()
+		},
+		GotFirstResponseByte: func() { rt.mark(&rt.firstByte) },
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), ct)), rt
+}
+
+func (rt *requestTrace) mark(at *time.Time) {
+	rt.mu.Lock()
+	*at = time.Now()
+	rt.mu.Unlock()
+}
+
+// markOnce keeps the first time, e.g. of parallel dial attempts.
+func (rt *requestTrace) markOnce(at *time.Time) {
+	rt.mu.Lock()
+	if at.IsZero() {
+		*at = time.Now()
+	}
+	rt.mu.Unlock()
+}
+
+// failed logs a transport error.
+func (rt *requestTrace) failed(err error) {
+	rt.t.printf("! [%d] %v (%s)\n", rt.id, err, rt.timings(time.Now()))
+}
+
+// wrap logs response headers and defers the summary line until the body is
+// closed, when the size and total time are known.
+func (rt *requestTrace) wrap(resp *http.Response) {
+	if rt.t.level >= TraceFull {
+		var b strings.Builder
+		fmt.Fprintf(&b, "< [%d] %s %s\n", rt.id, resp.Proto, resp.Status)
+		writeTraceHeaders(&b, "<", rt.id, resp.Header)
+		rt.t.printf("%s", b.String())
+	}
+	resp.Body = &tracedBody{ReadCloser: resp.Body, rt: rt, status: resp.Status}
+}
+
+func (rt *requestTrace) timings(end time.Time) string {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	parts := []string{}
+	if rt.reused {
+		parts = append(parts, "reused connection")
+	} else {
+		parts = append(parts,
+			"dns "+span(rt.dnsStart, rt.dnsDone),
+			"connect "+span(rt.connStart, rt.connDone),
+			"tls "+span(rt.tlsStart, rt.tlsDone))
+	}
+	parts = append(parts, "first byte "+span(rt.start, rt.firstByte), "total "+span(rt.start, end))
+	return strings.Join(parts, ", ")
+}
+
+func span(from, to time.Time) string {
+	if from.IsZero() || to.IsZero() {
+		return "-"
+	}
+	return to.Sub(from).Round(time.Microsecond).String()
+}
+
+type tracedBody struct {
+	io.ReadCloser
+	rt     *requestTrace
+	status string
+	n      int64
+	once   sync.Once
+}
+
+func (b *tracedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+func (b *tracedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		b.rt.t.printf("< [%d] %s, %d bytes (%s)\n", b.rt.id, b.status, b.n, b.rt.timings(time.Now()))
+	})
+	return err
+}
+
+// traceCached logs a GET answered from the response cache.
+func (t *tracer) traceCached(endpoint string, size int) {
+	t.printf("= [%d] GET %s served from cache, %d bytes\n", t.seq.Add(1), endpoint, size)
+}
+
+func writeTraceHeaders(b *strings.Builder, dir string, id int64, h http.Header) {
+	for _, name := range sortedHeaderNames(h) {
+		for _, value := range h.Values(name) {
+			if sensitive(name, sensitiveHeaderParts) {
+				if strings.EqualFold(name, "Authorization") {
+					value = redactAuthorization(value)
+				} else {
+					value = redacted
+				}
+			}
+			fmt.Fprintf(b, "%s [%d] %s: %s\n", dir, id, name, value)
+		}
+	}
+}
+
+// redactURL hides sensitive query values and session ids in paths such as
+// /system/sessions/{id}.
+func redactURL(u *url.URL) string {
+	c := *u
+	if before, after, ok := strings.Cut(c.Path, "/sessions/"); ok && after != "" {
+		rest := ""
+		if i := strings.Index(after, "/"); i >= 0 {
+			rest = after[i:]
+		}
+		c.Path = before + "/sessions/" + redactedURLValue + rest
+		c.RawPath = ""
+	}
+	q := c.Query()
+	changed := false
+	for key := range q {
+		if sensitive(key, sensitiveQueryParts) {
+			q.Set(key, redactedURLValue)
+			changed = true
+		}
+	}
+	if changed {
+		c.RawQuery = q.Encode()
+	}
+	return c.String()
+}
+
+func sensitive(name string, parts []string) bool {
+	name = strings.ToLower(name)
+	for _, p := range parts {
+		if strings.Contains(name, p) {
+			return true
+		}
+	}
+	return false
+}
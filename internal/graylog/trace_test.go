@@ -0,0 +1,77 @@
+package graylog
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestTraceLogsRequestsWithRedaction(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "authentication=secret-session")
+		_, _ = w.Write([]byte(`{"session_id":"secret-session"}`))
+	}))
+	defer srv.Close()
+
+	var log bytes.Buffer
+	c, err := NewClient(ClientConfig{BaseURL: srv.URL, APIBase: "/api", Session: "secret-session", Trace: TraceFull, TraceOut: &log})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	if err := c.Do(context.Background(), http.MethodPost, "/system/sessions", SessionRequest{Username: "u", Password: "hunter2"}, &map[string]any{}); err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	out := log.String()
+	for _, secret := range []string{"secret-session", "hunter2", "c2VjcmV0LXNlc3Npb24"} {
+		if strings.Contains(out, secret) {
+			t.Fatalf("trace leaked %q:\n%s", secret, out)
+		}
+	}
+	for _, want := range []string{"> [1] POST " + srv.URL + "/api/system/sessions", "Authorization: Basic <redacted>", "Set-Cookie: <redacted>", "< [1] 200 OK, 31 bytes (dns -", "first byte ", "total "} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("trace missing %q:\n%s", want, out)
+		}
+	}
+
+	log.Reset()
+	c, _ = NewClient(ClientConfig{BaseURL: srv.URL, APIBase: "/api", Token: "t", Trace: TraceVerbose, TraceOut: &log})
+	if _, err := c.Call(context.Background(), CallRequest{Method: http.MethodGet, Path: "/streams"}); err != nil {
+		t.Fatalf("call: %v", err)
+	}
+	if lines := strings.Count(log.String(), "\n"); lines != 2 || strings.Contains(log.String(), "Authorization") {
+		t.Fatalf("verbose should log two lines without headers:\n%s", log.String())
+	}
+}
+
+func TestTraceLogsTransportErrors(t *testing.T) {
+	t.Parallel()
+
+	var log bytes.Buffer
+	c, err := NewClient(ClientConfig{BaseURL: "http://127.0.0.1:1", APIBase: "/api", Token: "t", Trace: TraceVerbose, TraceOut: &log})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	if err := c.Do(context.Background(), http.MethodGet, "/system", nil, nil); err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(log.String(), "! [1] ") {
+		t.Fatalf("expected error line:\n%s", log.String())
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	t.Parallel()
+
+	u, _ := url.Parse("https://gl/api/system/sessions/abc123/x?session_id=abc123&page=2")
+	got := redactURL(u)
+	if strings.Contains(got, "abc123") || !strings.Contains(got, "/sessions/REDACTED/x") || !strings.Contains(got, "page=2") {
+		t.Fatalf("unexpected redacted URL %q", got)
+	}
+}
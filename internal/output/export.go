@@ -0,0 +1,79 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// CopyCSVExport reads a CSV message export and re-writes it to dst as csv or
+// ndjson, one record at a time. The CSV header is only written when
+// writeHeader is set so several exports can be appended to one file.
+func CopyCSVExport(dst io.Writer, src io.Reader, format string, writeHeader bool) (int, error) {
+	r := csv.NewReader(src)
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	r.ReuseRecord = true
+
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read export header: %w", err)
+	}
+	header = append([]string(nil), header...)
+
+	var (
+		cw  *csv.Writer
+		enc *json.Encoder
+	)
+	switch format {
+	case "csv":
+		cw = csv.NewWriter(dst)
+		if writeHeader {
+			if err := cw.Write(header); err != nil {
+				return 0, fmt.Errorf("write csv: %w", err)
+			}
+		}
+	case "ndjson":
+		enc = json.NewEncoder(dst)
+	default:
+		return 0, fmt.Errorf("unsupported export format %q (use csv|ndjson)", format)
+	}
+
+	n := 0
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return n, fmt.Errorf("read export record %d: %w", n+1, err)
+		}
+		if cw != nil {
+			err = cw.Write(record)
+		} else {
+			obj := make(map[string]string, len(header))
+			for i, h := range header {
+				if i < len(record) {
+					obj[h] = record[i]
+				}
+			}
+			err = enc.Encode(obj)
+		}
+		if err != nil {
+			return n, fmt.Errorf("write %s: %w", format, err)
+		}
+		n++
+	}
+	if cw != nil {
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return n, fmt.Errorf("write csv: %w", err)
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,62 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const sampleExport = "\"timestamp\",\"source\",\"message\"\n" +
+	"\"2026-02-18T10:00:00.000Z\",\"nginx-1\",\"GET /a, 200\"\n" +
+	"\"2026-02-18T10:00:01.000Z\",\"nginx-2\",\"multi\nline\"\n"
+
+func TestCopyCSVExportCSV(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	n, err := CopyCSVExport(&buf, strings.NewReader(sampleExport), "csv", true)
+	if err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 records, got %d", n)
+	}
+	want := "timestamp,source,message\n2026-02-18T10:00:00.000Z,nginx-1,\"GET /a, 200\"\n2026-02-18T10:00:01.000Z,nginx-2,\"multi\nline\"\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected csv:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if _, err := CopyCSVExport(&buf, strings.NewReader(sampleExport), "csv", false); err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	if strings.HasPrefix(buf.String(), "timestamp") {
+		t.Fatalf("expected header to be skipped:\n%s", buf.String())
+	}
+}
+
+func TestCopyCSVExportNDJSON(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if _, err := CopyCSVExport(&buf, strings.NewReader(sampleExport), "ndjson", true); err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d:\n%s", len(lines), buf.String())
+	}
+	if lines[1] != `{"message":"multi\nline","source":"nginx-2","timestamp":"2026-02-18T10:00:01.000Z"}` {
+		t.Fatalf("unexpected ndjson line: %s", lines[1])
+	}
+}
+
+func TestCopyCSVExportEmpty(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	n, err := CopyCSVExport(&buf, strings.NewReader(""), "csv", true)
+	if err != nil || n != 0 || buf.Len() != 0 {
+		t.Fatalf("expected empty export, got n=%d err=%v out=%q", n, err, buf.String())
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 
 	"github.com/jedib0t/go-pretty/v6/table"
@@ -20,21 +21,48 @@ func PrintJSON(w io.Writer, v any) error {
 	return err
 }
 
+func PrintJSONLine(w io.Writer, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal json: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
+
+func PrintRowLine(w io.Writer, values []any, maxWidth int) error {
+	cells := make([]string, 0, len(values))
+	for _, v := range values {
+		cells = append(cells, FormatCell(v, maxWidth))
+	}
+	_, err := fmt.Fprintln(w, strings.Join(cells, "\t"))
+	return err
+}
+
 func PrintKeyValueTable(w io.Writer, m map[string]any) error {
 	tw := table.NewWriter()
 	tw.AppendHeader(table.Row{"KEY", "VALUE"})
-	for k, v := range m {
-		tw.AppendRow(table.Row{k, fmt.Sprintf("%v", v)})
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		tw.AppendRow(table.Row{k, fmt.Sprintf("%v", m[k])})
 	}
 	_, err := fmt.Fprintln(w, tw.Render())
 	return err
 }
 
 func PrintSearchTable(w io.Writer, resp graylog.SearchMessagesResponse, maxWidth int) error {
+	return PrintRowsTable(w, resp.Schema, resp.DataRows, maxWidth)
+}
+
+func PrintRowsTable(w io.Writer, schema []graylog.SearchSchemaColumn, dataRows [][]any, maxWidth int) error {
 	tw := table.NewWriter()
 
-	headers := make(table.Row, 0, len(resp.Schema))
-	for i, col := range resp.Schema {
+	headers := make(table.Row, 0, len(schema))
+	for i, col := range schema {
 		name := col.Name
 		if name == "" {
 			name = col.Field
@@ -46,18 +74,14 @@ func PrintSearchTable(w io.Writer, resp graylog.SearchMessagesResponse, maxWidth
 	}
 	tw.AppendHeader(headers)
 
-	for _, r := range resp.DataRows {
+	for _, r := range dataRows {
 		row := make(table.Row, 0, len(headers))
 		for i := range headers {
 			var value any
 			if i < len(r) {
 				value = r[i]
 			}
-			cell := fmt.Sprintf("%v", value)
-			if maxWidth > 0 {
-				cell = truncate(cell, maxWidth)
-			}
-			row = append(row, cell)
+			row = append(row, FormatCell(value, maxWidth))
 		}
 		tw.AppendRow(row)
 	}
@@ -73,6 +97,14 @@ func PrintSearchTable(w io.Writer, resp graylog.SearchMessagesResponse, maxWidth
 	return err
 }
 
+func FormatCell(v any, maxWidth int) string {
+	cell := fmt.Sprintf("%v", v)
+	if maxWidth > 0 {
+		cell = truncate(cell, maxWidth)
+	}
+	return cell
+}
+
 func truncate(s string, max int) string {
 	if max <= 0 || len(s) <= max {
 		return s
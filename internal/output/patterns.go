@@ -0,0 +1,40 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/dsantic/graylog-cli/internal/graylog"
+	"github.com/dsantic/graylog-cli/internal/patterns"
+)
+
+func PrintPatternTable(w io.Writer, list []patterns.Pattern, total, maxWidth int) error {
+	tw := table.NewWriter()
+	tw.AppendHeader(table.Row{"COUNT", "SHARE", "FIRST", "LAST", "PATTERN", "EXAMPLE"})
+	for _, p := range list {
+		share := 0.0
+		if total > 0 {
+			share = 100 * float64(p.Count) / float64(total)
+		}
+		tw.AppendRow(table.Row{
+			p.Count,
+			fmt.Sprintf("%.1f%%", share),
+			formatPatternTime(p.First),
+			formatPatternTime(p.Last),
+			FormatCell(p.Pattern, maxWidth),
+			FormatCell(p.Example, maxWidth),
+		})
+	}
+	_, err := fmt.Fprintln(w, tw.Render())
+	return err
+}
+
+func formatPatternTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(graylog.TimestampLayout)
+}
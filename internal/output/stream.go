@@ -0,0 +1,121 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/dsantic/graylog-cli/internal/graylog"
+)
+
+// SearchStream writes search pages as they arrive. In json mode it produces
+// the same document shape as graylog.SearchNormalized without buffering rows;
+// table mode collects the rows and renders a single table on Close.
+type SearchStream struct {
+	w        io.Writer
+	format   string
+	maxWidth int
+	started  bool
+	rows     int
+	metadata map[string]any
+	table    graylog.SearchMessagesResponse
+	err      error
+}
+
+func NewSearchStream(w io.Writer, format string, maxWidth int) *SearchStream {
+	return &SearchStream{w: w, format: format, maxWidth: maxWidth}
+}
+
+func (s *SearchStream) WritePage(resp graylog.SearchMessagesResponse) error {
+	if s.metadata == nil && resp.Metadata != nil {
+		s.metadata = resp.Metadata
+	}
+	if s.format != "json" {
+		if s.table.Schema == nil {
+			s.table.Schema = resp.Schema
+		}
+		s.table.DataRows = append(s.table.DataRows, resp.DataRows...)
+		s.rows += len(resp.DataRows)
+		return nil
+	}
+
+	if !s.started {
+		if err := s.writeHeader(resp.Schema); err != nil {
+			return err
+		}
+	}
+	for _, row := range graylog.NormalizeSearchResponse(resp).Rows {
+		b, err := json.MarshalIndent(row, "    ", "  ")
+		if err != nil {
+			return fmt.Errorf("marshal json: %w", err)
+		}
+		sep := ",\n    "
+		if s.rows == 0 {
+			sep = "\n    "
+		}
+		if _, err := fmt.Fprint(s.w, sep, string(b)); err != nil {
+			return err
+		}
+		s.rows++
+	}
+	return nil
+}
+
+// Abort finishes the document after a failed search, recording err in the
+// metadata so the rows written so far stay valid JSON.
+func (s *SearchStream) Abort(err error) error {
+	s.err = err
+	return s.Close()
+}
+
+// Close finishes the document, adding total_fetched to the metadata of the
+// first page.
+func (s *SearchStream) Close() error {
+	metadata := make(map[string]any, len(s.metadata)+2)
+	for k, v := range s.metadata {
+		metadata[k] = v
+	}
+	metadata["total_fetched"] = s.rows
+	if s.err != nil {
+		metadata["error"] = s.err.Error()
+	}
+
+	if s.format != "json" {
+		if s.table.Schema != nil || len(s.table.DataRows) > 0 {
+			if err := PrintSearchTable(s.w, s.table, s.maxWidth); err != nil {
+				return err
+			}
+		}
+		_, err := fmt.Fprintf(s.w, "total fetched: %d\n", s.rows)
+		return err
+	}
+
+	if !s.started {
+		if err := s.writeHeader(nil); err != nil {
+			return err
+		}
+	}
+	b, err := json.MarshalIndent(metadata, "  ", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal json: %w", err)
+	}
+	tail := "\n  ],\n  \"metadata\": "
+	if s.rows == 0 {
+		tail = "],\n  \"metadata\": "
+	}
+	_, err = fmt.Fprint(s.w, tail, string(b), "\n}\n")
+	return err
+}
+
+func (s *SearchStream) writeHeader(schema []graylog.SearchSchemaColumn) error {
+	if schema == nil {
+		schema = []graylog.SearchSchemaColumn{}
+	}
+	b, err := json.MarshalIndent(schema, "  ", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal json: %w", err)
+	}
+	s.started = true
+	_, err = fmt.Fprint(s.w, "{\n  \"schema\": ", string(b), ",\n  \"rows\": [")
+	return err
+}
@@ -0,0 +1,98 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dsantic/graylog-cli/internal/graylog"
+)
+
+func streamPage(values ...string) graylog.SearchMessagesResponse {
+	resp := graylog.SearchMessagesResponse{
+		Schema:   []graylog.SearchSchemaColumn{{Name: "message"}},
+		Metadata: map[string]any{"page": values},
+	}
+	for _, v := range values {
+		resp.DataRows = append(resp.DataRows, []any{v})
+	}
+	return resp
+}
+
+func TestSearchStreamJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		pages [][]string
+		err   error
+		want  int
+	}{
+		{name: "no pages"},
+		{name: "one page", pages: [][]string{{"a", "b"}}, want: 2},
+		{name: "many pages", pages: [][]string{{"a", "b"}, {"c"}, {"d", "e"}}, want: 5},
+		{name: "aborted", pages: [][]string{{"a"}}, err: errors.New("boom"), want: 1},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			s := NewSearchStream(&buf, "json", 0)
+			for _, p := range tc.pages {
+				if err := s.WritePage(streamPage(p...)); err != nil {
+					t.Fatalf("write page: %v", err)
+				}
+			}
+			var err error
+			if tc.err != nil {
+				err = s.Abort(tc.err)
+			} else {
+				err = s.Close()
+			}
+			if err != nil {
+				t.Fatalf("close: %v", err)
+			}
+
+			var doc struct {
+				Schema   []graylog.SearchSchemaColumn `json:"schema"`
+				Rows     []map[string]any             `json:"rows"`
+				Metadata map[string]any               `json:"metadata"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+				t.Fatalf("invalid json: %v\n%s", err, buf.String())
+			}
+			if len(doc.Rows) != tc.want || doc.Metadata["total_fetched"] != float64(tc.want) {
+				t.Fatalf("expected %d rows, got %d (metadata %v)", tc.want, len(doc.Rows), doc.Metadata)
+			}
+			if tc.err != nil && doc.Metadata["error"] != tc.err.Error() {
+				t.Fatalf("expected the error in metadata, got %v", doc.Metadata)
+			}
+		})
+	}
+}
+
+func TestSearchStreamTableSingleHeader(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	s := NewSearchStream(&buf, "table", 0)
+	for _, p := range [][]string{{"a", "b"}, {"c"}} {
+		if err := s.WritePage(streamPage(p...)); err != nil {
+			t.Fatalf("write page: %v", err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	out := buf.String()
+	if n := strings.Count(out, "MESSAGE"); n != 1 {
+		t.Fatalf("expected one header, got %d:\n%s", n, out)
+	}
+	if !strings.Contains(out, "total fetched: 3") {
+		t.Fatalf("missing total:\n%s", out)
+	}
+}
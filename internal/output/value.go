@@ -0,0 +1,91 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// PrintValueTable renders a decoded JSON document as a table: objects as
+// KEY/VALUE rows, arrays of objects with one column per key, other arrays as a
+// single VALUE column. Nested values are shown as compact JSON.
+func PrintValueTable(w io.Writer, v any, maxWidth int) error {
+	tw := table.NewWriter()
+	switch doc := v.(type) {
+	case map[string]any:
+		tw.AppendHeader(table.Row{"KEY", "VALUE"})
+		for _, k := range sortedKeys(doc) {
+			tw.AppendRow(table.Row{k, valueCell(doc[k], maxWidth)})
+		}
+	case []any:
+		if columns, ok := objectColumns(doc); ok {
+			header := make(table.Row, 0, len(columns))
+			for _, c := range columns {
+				header = append(header, strings.ToUpper(c))
+			}
+			tw.AppendHeader(header)
+			for _, item := range doc {
+				obj := item.(map[string]any)
+				row := make(table.Row, 0, len(columns))
+				for _, c := range columns {
+					row = append(row, valueCell(obj[c], maxWidth))
+				}
+				tw.AppendRow(row)
+			}
+		} else {
+			tw.AppendHeader(table.Row{"VALUE"})
+			for _, item := range doc {
+				tw.AppendRow(table.Row{valueCell(item, maxWidth)})
+			}
+		}
+	default:
+		_, err := fmt.Fprintln(w, valueCell(v, 0))
+		return err
+	}
+	_, err := fmt.Fprintln(w, tw.Render())
+	return err
+}
+
+// objectColumns returns the union of keys when every item is an object.
+func objectColumns(items []any) ([]string, bool) {
+	if len(items) == 0 {
+		return nil, false
+	}
+	seen := map[string]bool{}
+	for _, item := range items {
+		obj, ok := item.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		for k := range obj {
+			seen[k] = true
+		}
+	}
+	return sortedKeys(seen), true
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func valueCell(v any, maxWidth int) string {
+	switch v.(type) {
+	case nil:
+		return ""
+	case map[string]any, []any:
+		b, err := json.Marshal(v)
+		if err == nil {
+			return FormatCell(string(b), maxWidth)
+		}
+	}
+	return FormatCell(v, maxWidth)
+}
@@ -0,0 +1,42 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPrintValueTable(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	doc := []any{
+		map[string]any{"id": "a", "title": "All messages"},
+		map[string]any{"id": "b", "rules": []any{"x"}},
+	}
+	if err := PrintValueTable(&buf, doc, 0); err != nil {
+		t.Fatalf("print: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"ID", "RULES", "TITLE", "All messages", `["x"]`} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	if err := PrintValueTable(&buf, map[string]any{"total": 2.0, "meta": map[string]any{"k": "v"}}, 0); err != nil {
+		t.Fatalf("print: %v", err)
+	}
+	if !strings.Contains(buf.String(), `{"k":"v"}`) || !strings.Contains(buf.String(), "KEY") {
+		t.Fatalf("unexpected object table:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := PrintValueTable(&buf, "plain", 0); err != nil {
+		t.Fatalf("print: %v", err)
+	}
+	if buf.String() != "plain\n" {
+		t.Fatalf("unexpected scalar output %q", buf.String())
+	}
+}
@@ -0,0 +1,95 @@
+package patterns
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Pattern struct {
+	Pattern string    `json:"pattern"`
+	Count   int       `json:"count"`
+	First   time.Time `json:"first"`
+	Last    time.Time `json:"last"`
+	Example string    `json:"example"`
+}
+
+var (
+	uuidRe   = regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`)
+	ipv4Re   = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}(?::\d{1,5})?\b`)
+	ipv6Re   = regexp.MustCompile(`(?i)\b(?:[0-9a-f]{1,4}:){7}[0-9a-f]{1,4}\b|\b(?:[0-9a-f]{1,4}:){1,6}:(?:[0-9a-f]{1,4}(?::[0-9a-f]{1,4})*)?|::(?:[0-9a-f]{1,4}(?::[0-9a-f]{1,4})*)\b`)
+	hexRe    = regexp.MustCompile(`\b(?:0[xX][0-9a-fA-F]+|[0-9a-fA-F]{8,})\b`)
+	numberRe = regexp.MustCompile(`\b\d+(?:\.\d+)?`)
+)
+
+// Mask replaces the variable parts of a log message (UUIDs, IP addresses, hex
+// strings and numbers) with placeholders so messages from the same template
+// compare equal.
+func Mask(msg string) string {
+	msg = uuidRe.ReplaceAllString(msg, "<UUID>")
+	msg = ipv4Re.ReplaceAllString(msg, "<IP>")
+	msg = ipv6Re.ReplaceAllString(msg, "<IP>")
+	msg = hexRe.ReplaceAllStringFunc(msg, func(s string) string {
+		if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+			return "<HEX>"
+		}
+		// Pure digits are left to the number mask and pure letters are words.
+		if strings.IndexAny(s, "0123456789") < 0 || strings.IndexAny(s, "abcdefABCDEF") < 0 {
+			return s
+		}
+		return "<HEX>"
+	})
+	return numberRe.ReplaceAllString(msg, "<NUM>")
+}
+
+// Clusterer groups messages by their masked template. It keeps one entry per
+// pattern, so memory grows with the number of distinct patterns rather than
+// the number of messages.
+type Clusterer struct {
+	byPattern map[string]*Pattern
+	total     int
+}
+
+func NewClusterer() *Clusterer {
+	return &Clusterer{byPattern: map[string]*Pattern{}}
+}
+
+func (c *Clusterer) Add(msg string, ts time.Time) {
+	c.total++
+	key := Mask(msg)
+	p, ok := c.byPattern[key]
+	if !ok {
+		p = &Pattern{Pattern: key, Example: msg, First: ts, Last: ts}
+		c.byPattern[key] = p
+	}
+	p.Count++
+	if ts.IsZero() {
+		return
+	}
+	if p.First.IsZero() || ts.Before(p.First) {
+		p.First = ts
+	}
+	if ts.After(p.Last) {
+		p.Last = ts
+	}
+}
+
+func (c *Clusterer) Total() int {
+	return c.total
+}
+
+// Patterns returns the patterns sorted by count, most frequent first.
+func (c *Clusterer) Patterns() []Pattern {
+	out := make([]Pattern, 0, len(c.byPattern))
+	for _, p := range c.byPattern {
+		out = append(out, *p)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Pattern < out[j].Pattern
+	})
+	return out
+}
@@ -0,0 +1,61 @@
+package patterns
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMask(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"user 42 logged in", "user <NUM> logged in"},
+		{"took 12.5ms", "took <NUM>ms"},
+		{"request 3f2504e0-4f89-11d3-9a0c-0305e82c3301 failed", "request <UUID> failed"},
+		{"connect to 10.0.0.12:5432 refused", "connect to <IP> refused"},
+		{"peer fe80::1ff:fe23:4567:890a down", "peer <IP> down"},
+		{"commit a1b2c3d4e5f6 at 0xDEADBEEF", "commit <HEX> at <HEX>"},
+		{"accessed decade facade", "accessed decade facade"},
+		{"order 12345678 shipped", "order <NUM> shipped"},
+		{"http2 upstream v1", "http2 upstream v1"},
+		{"job req-42 done", "job req-<NUM> done"},
+	}
+	for _, tc := range tests {
+		if got := Mask(tc.in); got != tc.want {
+			t.Errorf("Mask(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestClustererGroupsAndSorts(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 2, 18, 10, 0, 0, 0, time.UTC)
+	c := NewClusterer()
+	c.Add("user 1 logged in from 10.0.0.1", base.Add(2*time.Second))
+	c.Add("disk full on /dev/sda1", base.Add(time.Second))
+	c.Add("user 22 logged in from 10.0.0.2", base)
+	c.Add("user 333 logged in from 10.0.0.3", base.Add(5*time.Second))
+	c.Add("no timestamp", time.Time{})
+
+	got := c.Patterns()
+	if c.Total() != 5 || len(got) != 3 {
+		t.Fatalf("unexpected clustering: total %d, %+v", c.Total(), got)
+	}
+	top := got[0]
+	if top.Pattern != "user <NUM> logged in from <IP>" || top.Count != 3 {
+		t.Fatalf("unexpected top pattern %+v", top)
+	}
+	if !top.First.Equal(base) || !top.Last.Equal(base.Add(5*time.Second)) {
+		t.Fatalf("unexpected first/last %s %s", top.First, top.Last)
+	}
+	if top.Example != "user 1 logged in from 10.0.0.1" {
+		t.Fatalf("unexpected example %q", top.Example)
+	}
+	if got[1].Pattern != "disk full on /dev/sda1" || got[2].Pattern != "no timestamp" || !got[2].First.IsZero() {
+		t.Fatalf("unexpected order %+v", got)
+	}
+}
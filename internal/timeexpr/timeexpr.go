@@ -0,0 +1,158 @@
+package timeexpr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var units = map[string]time.Duration{
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+	"d":  24 * time.Hour,
+	"w":  7 * 24 * time.Hour,
+}
+
+// ParseDuration accepts Go-style durations extended with d (days) and w
+// (weeks), e.g. 15m, 2h30m, 1d12h.
+func ParseDuration(s string) (time.Duration, error) {
+	raw := s
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("invalid duration %q: empty", raw)
+	}
+	var total time.Duration
+	for pos := 0; pos < len(s); {
+		start := pos
+		for pos < len(s) && s[pos] >= '0' && s[pos] <= '9' {
+			pos++
+		}
+		if pos == start {
+			return 0, fmt.Errorf("invalid duration %q: expected a number at position %d", raw, start+1)
+		}
+		n, err := strconv.ParseInt(s[start:pos], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", raw, err)
+		}
+		unitStart := pos
+		for pos < len(s) && (s[pos] < '0' || s[pos] > '9') {
+			pos++
+		}
+		unit := s[unitStart:pos]
+		if unit == "" {
+			return 0, fmt.Errorf("invalid duration %q: missing unit after %d (use ms, s, m, h, d, w)", raw, n)
+		}
+		mult, ok := units[unit]
+		if !ok {
+			return 0, fmt.Errorf("invalid duration %q: unknown unit %q (use ms, s, m, h, d, w)", raw, unit)
+		}
+		total += time.Duration(n) * mult
+	}
+	return total, nil
+}
+
+var absoluteLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ParseTime resolves a point in time relative to now. Accepted forms:
+//
+//	now, today, yesterday, optionally followed by HH:MM[:SS]
+//	HH:MM[:SS] (today)
+//	RFC3339 with or without offset, or a date (zoneless values use now's location)
+//	Unix epoch seconds or milliseconds
+//	15m ago, -15m, now-15m
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	raw := s
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return time.Time{}, fmt.Errorf("invalid time %q: empty", raw)
+	}
+
+	switch {
+	case s == "now":
+		return now, nil
+	case strings.HasPrefix(s, "now-"):
+		return ago(raw, s[len("now-"):], now)
+	case strings.HasPrefix(s, "-"):
+		return ago(raw, s[1:], now)
+	case strings.HasSuffix(s, " ago"):
+		return ago(raw, strings.TrimSpace(strings.TrimSuffix(s, " ago")), now)
+	}
+
+	day, rest, hasClock := strings.Cut(s, " ")
+	switch day {
+	case "today", "yesterday":
+		base := midnight(now)
+		if day == "yesterday" {
+			base = base.AddDate(0, 0, -1)
+		}
+		if !hasClock {
+			return base, nil
+		}
+		return atClock(raw, base, strings.TrimSpace(rest))
+	}
+
+	if isDigits(s) {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time %q: %w", raw, err)
+		}
+		if len(s) >= 13 {
+			return time.UnixMilli(n).In(now.Location()), nil
+		}
+		return time.Unix(n, 0).In(now.Location()), nil
+	}
+
+	if strings.Contains(s, ":") && !strings.Contains(s, "-") {
+		return atClock(raw, midnight(now), s)
+	}
+
+	upper := strings.ToUpper(strings.TrimSpace(raw))
+	for _, layout := range absoluteLayouts {
+		if t, err := time.ParseInLocation(layout, upper, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use now, today, yesterday [HH:MM], HH:MM, RFC3339 (2026-02-18T10:00:00Z), epoch seconds/millis, or a duration like \"15m ago\"", raw)
+}
+
+func ago(raw, expr string, now time.Time) (time.Time, error) {
+	d, err := ParseDuration(expr)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: %w", raw, err)
+	}
+	return now.Add(-d), nil
+}
+
+func atClock(raw string, day time.Time, clock string) (time.Time, error) {
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		if t, err := time.Parse(layout, clock); err == nil {
+			y, m, d := day.Date()
+			return time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), 0, day.Location()), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: clock %q must be HH:MM or HH:MM:SS", raw, clock)
+}
+
+func midnight(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
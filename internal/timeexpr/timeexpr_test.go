@@ -0,0 +1,85 @@
+package timeexpr
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]time.Duration{
+		"15m":    15 * time.Minute,
+		"2h30m":  2*time.Hour + 30*time.Minute,
+		"1d12h":  36 * time.Hour,
+		"1w":     7 * 24 * time.Hour,
+		"500ms":  500 * time.Millisecond,
+		" 90s ":  90 * time.Second,
+		"1h1m1s": time.Hour + time.Minute + time.Second,
+	}
+	for in, want := range tests {
+		got, err := ParseDuration(in)
+		if err != nil || got != want {
+			t.Fatalf("%q: got %s (err %v), want %s", in, got, err, want)
+		}
+	}
+
+	errs := map[string]string{
+		"":    "empty",
+		"15":  "missing unit",
+		"m":   "expected a number at position 1",
+		"2x":  `unknown unit "x"`,
+		"1h-": `unknown unit "h-"`,
+	}
+	for in, want := range errs {
+		_, err := ParseDuration(in)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%q: expected error containing %q, got %v", in, want, err)
+		}
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 2, 18, 14, 30, 0, 0, time.UTC)
+	tests := map[string]time.Time{
+		"now":                       now,
+		"NOW":                       now,
+		"15m ago":                   now.Add(-15 * time.Minute),
+		"-2h30m":                    now.Add(-150 * time.Minute),
+		"now-1d":                    now.AddDate(0, 0, -1),
+		"today":                     time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC),
+		"yesterday 10:00":           time.Date(2026, 2, 17, 10, 0, 0, 0, time.UTC),
+		"yesterday 10:00:30":        time.Date(2026, 2, 17, 10, 0, 30, 0, time.UTC),
+		"09:15":                     time.Date(2026, 2, 18, 9, 15, 0, 0, time.UTC),
+		"2026-02-18T10:00:00Z":      time.Date(2026, 2, 18, 10, 0, 0, 0, time.UTC),
+		"2026-02-18T12:00:00+02:00": time.Date(2026, 2, 18, 10, 0, 0, 0, time.UTC),
+		"2026-02-18T10:00:00.250Z":  time.Date(2026, 2, 18, 10, 0, 0, 250e6, time.UTC),
+		"2026-02-18 10:00":          time.Date(2026, 2, 18, 10, 0, 0, 0, time.UTC),
+		"2026-02-18":                time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC),
+		"1771408800":                time.Date(2026, 2, 18, 10, 0, 0, 0, time.UTC),
+		"1771408800123":             time.Date(2026, 2, 18, 10, 0, 0, 123e6, time.UTC),
+	}
+	for in, want := range tests {
+		got, err := ParseTime(in, now)
+		if err != nil || !got.Equal(want) {
+			t.Fatalf("%q: got %s (err %v), want %s", in, got, err, want)
+		}
+	}
+
+	errs := map[string]string{
+		"":              "empty",
+		"tomorrow":      "use now, today",
+		"yesterday 25h": `clock "25h"`,
+		"10 ago":        "missing unit",
+		"2026-13-01":    "use now, today",
+	}
+	for in, want := range errs {
+		_, err := ParseTime(in, now)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%q: expected error containing %q, got %v", in, want, err)
+		}
+	}
+}